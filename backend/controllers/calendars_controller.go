@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/ics"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxCalendarDates      = 5000
+	maxCalendarImportSize = 1 << 20
+)
+
+type CalendarController struct {
+	DB *gorm.DB
+}
+
+type calendarRequest struct {
+	Name   string                `json:"name"`
+	Shared *bool                 `json:"shared"`
+	Dates  []models.CalendarDate `json:"dates"`
+}
+
+func NewCalendarController(db *gorm.DB) *CalendarController {
+	return &CalendarController{DB: db}
+}
+
+func (cc *CalendarController) GetCalendars(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var calendars []models.Calendar
+	cc.DB.Where("user_id = ? OR shared = ?", userID, true).Preload("Dates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date")
+	}).Order("name").Find(&calendars)
+	c.IndentedJSON(http.StatusOK, calendars)
+}
+
+func (cc *CalendarController) GetCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var calendar models.Calendar
+	err := cc.DB.Where("id = ? AND (user_id = ? OR shared = ?)", c.Param("id"), userID, true).Preload("Dates", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date")
+	}).First(&calendar).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve calendar"})
+		}
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, calendar)
+}
+
+func (cc *CalendarController) CreateCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req calendarRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid calendar data"})
+		return
+	}
+
+	dates, err := normalizeCalendarDates(req.Dates)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Name == "" || len(req.Name) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must be between 1 and 100 characters"})
+		return
+	}
+
+	calendar := models.Calendar{
+		ID:     uuid.NewString(),
+		UserID: userID.(string),
+		Name:   req.Name,
+		Shared: req.Shared != nil && *req.Shared,
+		Dates:  dates,
+	}
+	if err := cc.DB.Create(&calendar).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create calendar"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, calendar)
+}
+
+func (cc *CalendarController) UpdateCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	calendar, ok := cc.ownedCalendar(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	var req calendarRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid calendar data"})
+		return
+	}
+
+	if len(req.Name) > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name must be between 1 and 100 characters"})
+		return
+	}
+	if req.Name != "" {
+		calendar.Name = req.Name
+	}
+	if req.Shared != nil {
+		calendar.Shared = *req.Shared
+	}
+
+	var dates []models.CalendarDate
+	if req.Dates != nil {
+		var err error
+		if dates, err = normalizeCalendarDates(req.Dates); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Dates").Save(&calendar).Error; err != nil {
+			return err
+		}
+		if req.Dates == nil {
+			return nil
+		}
+		if err := tx.Where("calendar_id = ?", calendar.ID).Delete(&models.CalendarDate{}).Error; err != nil {
+			return err
+		}
+		for i := range dates {
+			dates[i].CalendarID = calendar.ID
+		}
+		if len(dates) > 0 {
+			if err := tx.Create(&dates).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update calendar"})
+		return
+	}
+
+	cc.DB.Where("calendar_id = ?", calendar.ID).Order("date").Find(&calendar.Dates)
+	c.IndentedJSON(http.StatusOK, calendar)
+}
+
+func (cc *CalendarController) DeleteCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	calendar, ok := cc.ownedCalendar(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	err := cc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Job{}).Where("calendar_id = ?", calendar.ID).Update("calendar_id", "").Error; err != nil {
+			return err
+		}
+		return tx.Delete(&calendar).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete calendar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "calendar deleted successfully"})
+}
+
+func (cc *CalendarController) ImportCalendar(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	calendar, ok := cc.ownedCalendar(c, userID.(string))
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxCalendarImportSize+1))
+	if err != nil || len(body) > maxCalendarImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "calendar file must be at most 1 MiB"})
+		return
+	}
+
+	events, err := ics.Parse(bytes.NewReader(body))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ics file: " + err.Error()})
+		return
+	}
+
+	var dates []models.CalendarDate
+	unexpanded := 0
+	for _, event := range events {
+		if event.UnexpandedRule != "" {
+			unexpanded++
+		}
+		for _, day := range event.Dates {
+			dates = append(dates, models.CalendarDate{Date: day.Format(models.CalendarDateLayout), Name: event.Summary})
+		}
+	}
+	dates, err = normalizeCalendarDates(dates)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The cap covers the whole calendar, so dates already stored count too.
+	var existing []string
+	if err := cc.DB.Model(&models.CalendarDate{}).Where("calendar_id = ?", calendar.ID).Pluck("date", &existing).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import calendar"})
+		return
+	}
+	stored := make(map[string]bool, len(existing))
+	for _, date := range existing {
+		stored[date] = true
+	}
+	total := len(existing)
+	for i := range dates {
+		dates[i].CalendarID = calendar.ID
+		if !stored[dates[i].Date] {
+			total++
+		}
+	}
+	if total > maxCalendarDates {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a calendar can hold at most 5000 dates"})
+		return
+	}
+	if len(dates) == 0 {
+		c.JSON(http.StatusOK, gin.H{"imported": 0, "events": len(events), "unexpanded_recurring_events": unexpanded})
+		return
+	}
+
+	result := cc.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&dates)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import calendar"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imported": result.RowsAffected, "events": len(events), "unexpanded_recurring_events": unexpanded})
+}
+
+func (cc *CalendarController) ownedCalendar(c *gin.Context, userID string) (models.Calendar, bool) {
+	var calendar models.Calendar
+	if err := cc.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&calendar).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "calendar not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve calendar"})
+		}
+		return calendar, false
+	}
+	return calendar, true
+}
+
+func normalizeCalendarDates(dates []models.CalendarDate) ([]models.CalendarDate, error) {
+	if len(dates) > maxCalendarDates {
+		return nil, errors.New("a calendar can hold at most 5000 dates")
+	}
+
+	seen := make(map[string]bool, len(dates))
+	normalized := make([]models.CalendarDate, 0, len(dates))
+	for _, date := range dates {
+		if _, err := time.Parse(models.CalendarDateLayout, date.Date); err != nil {
+			return nil, errors.New("dates must use the YYYY-MM-DD format")
+		}
+		if seen[date.Date] {
+			continue
+		}
+		seen[date.Date] = true
+		if len(date.Name) > 255 {
+			date.Name = strings.ToValidUTF8(date.Name[:255], "")
+		}
+		normalized = append(normalized, models.CalendarDate{Date: date.Date, Name: date.Name})
+	}
+	return normalized, nil
+}
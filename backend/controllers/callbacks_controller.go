@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/conan-flynn/cronnect/queue"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CallbackController struct {
+	DB    *gorm.DB
+	Queue *queue.QueueService
+}
+
+type callbackRequest struct {
+	Status       string `json:"status"`
+	Message      string `json:"message"`
+	ResponseCode int    `json:"response_code"`
+}
+
+func NewCallbackController(db *gorm.DB) *CallbackController {
+	return &CallbackController{DB: db, Queue: queue.NewQueueService()}
+}
+
+func (cc *CallbackController) CompleteExecution(c *gin.Context) {
+	var req callbackRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid callback data"})
+		return
+	}
+
+	if req.Status != "success" && req.Status != "failed" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be \"success\" or \"failed\""})
+		return
+	}
+
+	executionID, err := cc.Queue.RedeemCallbackToken(c.Param("token"))
+	if err != nil {
+		if err == queue.ErrInvalidCallbackToken {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify callback token"})
+		}
+		return
+	}
+
+	if err := cc.Queue.CompleteAsync(executionID, req.Status, req.Message, req.ResponseCode); err != nil {
+		if err == queue.ErrExecutionNotRunning {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to complete execution"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"execution_id": executionID, "status": req.Status})
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxPingBodySize = 4096
+
+type HeartbeatController struct {
+	DB *gorm.DB
+}
+
+func NewHeartbeatController(db *gorm.DB) *HeartbeatController {
+	return &HeartbeatController{DB: db}
+}
+
+func newPingToken() string {
+	return strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func (hc *HeartbeatController) PingSuccess(c *gin.Context) {
+	hc.recordPing(c, models.PingSuccess)
+}
+
+func (hc *HeartbeatController) PingStart(c *gin.Context) {
+	hc.recordPing(c, models.PingStart)
+}
+
+func (hc *HeartbeatController) PingFail(c *gin.Context) {
+	hc.recordPing(c, models.PingFail)
+}
+
+func (hc *HeartbeatController) recordPing(c *gin.Context, kind string) {
+	var job models.Job
+	if err := hc.DB.Where("ping_token = ? AND type = ?", c.Param("token"), models.JobTypeHeartbeat).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "unknown ping token"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve job"})
+		}
+		return
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxPingBodySize))
+	excerpt := strings.ToValidUTF8(strings.ReplaceAll(string(body), "\x00", ""), "")
+	now := time.Now()
+
+	if kind == models.PingStart {
+		execution := models.JobExecution{
+			ID:           uuid.NewString(),
+			JobID:        job.ID,
+			StartedAt:    now,
+			Status:       "running",
+			Trigger:      models.TriggerPing,
+			ResponseBody: excerpt,
+			RemoteIP:     c.ClientIP(),
+		}
+		if err := hc.DB.Create(&execution).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record ping"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+		return
+	}
+
+	status, state := "success", models.HeartbeatUp
+	if kind == models.PingFail {
+		status, state = "failed", models.HeartbeatDown
+	}
+
+	err := hc.DB.Transaction(func(tx *gorm.DB) error {
+		var execution models.JobExecution
+		err := tx.Where("job_id = ? AND trigger = ? AND status = ?", job.ID, models.TriggerPing, "running").
+			Order("started_at DESC").First(&execution).Error
+		if err == gorm.ErrRecordNotFound {
+			execution = models.JobExecution{
+				ID:        uuid.NewString(),
+				JobID:     job.ID,
+				StartedAt: now,
+				Trigger:   models.TriggerPing,
+			}
+		} else if err != nil {
+			return err
+		}
+
+		execution.Status = status
+		execution.FinishedAt = &now
+		execution.LatencyMs = now.Sub(execution.StartedAt).Milliseconds()
+		execution.RemoteIP = c.ClientIP()
+		if excerpt != "" {
+			execution.ResponseBody = excerpt
+		}
+		if err := tx.Save(&execution).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"heartbeat_state": state,
+			"last_ping_at":    now,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record ping"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
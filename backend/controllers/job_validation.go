@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/scheduler"
+	"github.com/conan-flynn/cronnect/worker"
+)
+
+func (jc *JobController) validateJob(userID string, job *models.Job) error {
+	if err := worker.ValidateJob(job); err != nil {
+		return err
+	}
+
+	if _, err := scheduler.LoadLocation(job.Timezone); err != nil {
+		return err
+	}
+
+	if err := validateScheduleWindow(job); err != nil {
+		return err
+	}
+
+	if job.SigningSecret != "" && !jc.secretExists(userID, job.SigningSecret) {
+		return errors.New("signing secret not found")
+	}
+
+	if err := jc.validateTLS(userID, job.TLS); err != nil {
+		return err
+	}
+
+	if job.CalendarID != "" && !jc.calendarVisible(userID, job.CalendarID) {
+		return errors.New("calendar not found")
+	}
+
+	return jc.validateDependencies(userID, job.ID, job.Dependencies)
+}
+
+func validateScheduleWindow(job *models.Job) error {
+	if job.RunAt != nil {
+		if job.Recurring() || len(job.Exclusions) > 0 {
+			return errors.New("run_at cannot be combined with schedules or exclusions")
+		}
+		if job.Type == models.JobTypeHeartbeat {
+			return errors.New("heartbeat jobs cannot use run_at")
+		}
+		if job.StartsAt != nil || job.EndsAt != nil || job.MaxRuns != 0 {
+			return errors.New("starts_at, ends_at and max_runs only apply to recurring jobs")
+		}
+		if job.Status != models.JobStatusCompleted && !job.RunAt.After(time.Now()) {
+			return errors.New("run_at must be in the future")
+		}
+	}
+	if job.Type == models.JobTypeHeartbeat && (job.StartsAt != nil || job.EndsAt != nil || job.MaxRuns != 0) {
+		return errors.New("heartbeat jobs cannot use starts_at, ends_at or max_runs")
+	}
+	if job.StartsAt != nil && job.EndsAt != nil && !job.EndsAt.After(*job.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if job.MaxRuns < 0 {
+		return errors.New("max_runs must not be negative")
+	}
+	if len(job.Schedules) > 0 || len(job.Exclusions) > 0 {
+		if job.Type == models.JobTypeHeartbeat {
+			return errors.New("heartbeat jobs support a single schedule only")
+		}
+		if !job.Recurring() {
+			return errors.New("exclusions require a schedule")
+		}
+		if _, err := scheduler.JobSchedule(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (jc *JobController) userTimezone(userID string) string {
+	var user models.User
+	jc.DB.Select("default_timezone").First(&user, "id = ?", userID)
+	return user.DefaultTimezone
+}
+
+// presentJob fills in next_run_at and renders the job's timestamps in its own
+// timezone.
+func presentJob(job *models.Job) {
+	loc := scheduler.JobLocation(job)
+	active := job.Status == "" || job.Status == models.JobStatusActive
+	if (job.Recurring() || job.RunAt != nil) && active && job.Type != models.JobTypeHeartbeat {
+		if schedule, err := scheduler.JobSchedule(job); err == nil {
+			if next := schedule.Next(time.Now()); !next.IsZero() {
+				next = next.In(loc)
+				job.NextRunAt = &next
+			}
+		}
+	}
+	for _, t := range []*time.Time{job.LastPingAt, job.PausedAt, job.PausedUntil, job.RunAt, job.StartsAt, job.EndsAt} {
+		if t != nil {
+			*t = t.In(loc)
+		}
+	}
+	for i := range job.Executions {
+		execution := &job.Executions[i]
+		execution.StartedAt = execution.StartedAt.In(loc)
+		if execution.FinishedAt != nil {
+			finishedAt := execution.FinishedAt.In(loc)
+			execution.FinishedAt = &finishedAt
+		}
+	}
+}
+
+func (jc *JobController) secretExists(userID, name string) bool {
+	var count int64
+	jc.DB.Model(&models.Secret{}).Where("user_id = ? AND name = ?", userID, name).Count(&count)
+	return count > 0
+}
+
+func (jc *JobController) calendarVisible(userID, calendarID string) bool {
+	var count int64
+	jc.DB.Model(&models.Calendar{}).Where("id = ? AND (user_id = ? OR shared = ?)", calendarID, userID, true).Count(&count)
+	return count > 0
+}
+
+func (jc *JobController) validateTLS(userID string, settings *models.JobTLS) error {
+	if settings == nil {
+		return nil
+	}
+	if (settings.ClientCertSecret == "") != (settings.ClientKeySecret == "") {
+		return errors.New("tls.client_cert_secret and tls.client_key_secret must be set together")
+	}
+	for _, name := range []string{settings.ClientCertSecret, settings.ClientKeySecret, settings.CACertSecret} {
+		if name != "" && !jc.secretExists(userID, name) {
+			return errors.New("tls secret not found: " + name)
+		}
+	}
+	return nil
+}
+
+func (jc *JobController) validateDependencies(userID, jobID string, dependencies []models.JobDependency) error {
+	if len(dependencies) == 0 {
+		return nil
+	}
+
+	var existing []models.JobDependency
+	jc.DB.Joins("JOIN jobs ON jobs.id = job_dependencies.downstream_job_id").
+		Where("jobs.user_id = ?", userID).
+		Find(&existing)
+
+	upstreams := make(map[string][]string)
+	for _, dep := range existing {
+		if dep.DownstreamJobID != jobID {
+			upstreams[dep.DownstreamJobID] = append(upstreams[dep.DownstreamJobID], dep.UpstreamJobID)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, dep := range dependencies {
+		switch dep.Condition {
+		case "", models.DependencyOnSuccess, models.DependencyOnFailure, models.DependencyAlways:
+		default:
+			return errors.New("invalid dependency condition: " + dep.Condition)
+		}
+		if dep.UpstreamJobID == jobID {
+			return errors.New("job cannot depend on itself")
+		}
+		if seen[dep.UpstreamJobID] {
+			return errors.New("duplicate dependency on job " + dep.UpstreamJobID)
+		}
+		seen[dep.UpstreamJobID] = true
+
+		var count int64
+		jc.DB.Model(&models.Job{}).Where("id = ? AND user_id = ?", dep.UpstreamJobID, userID).Count(&count)
+		if count == 0 {
+			return errors.New("upstream job not found: " + dep.UpstreamJobID)
+		}
+		upstreams[jobID] = append(upstreams[jobID], dep.UpstreamJobID)
+	}
+
+	visited := make(map[string]bool)
+	stack := append([]string{}, upstreams[jobID]...)
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if current == jobID {
+			return errors.New("dependencies would create a cycle")
+		}
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+		stack = append(stack, upstreams[current]...)
+	}
+
+	return nil
+}
+
+// clearableJobFields maps the JSON keys an update may set to null or empty
+// onto their struct fields; every other field keeps its value when omitted
+// or zero.
+var clearableJobFields = map[string]string{
+	"schedule":       "Schedule",
+	"schedules":      "Schedules",
+	"exclusions":     "Exclusions",
+	"run_at":         "RunAt",
+	"starts_at":      "StartsAt",
+	"ends_at":        "EndsAt",
+	"max_runs":       "MaxRuns",
+	"calendar_id":    "CalendarID",
+	"body":           "Body",
+	"headers":        "Headers",
+	"content_type":   "ContentType",
+	"assertions":     "Assertions",
+	"signing_secret": "SigningSecret",
+	"auth":           "Auth",
+	"tls":            "TLS",
+	"completion":     "Completion",
+}
+
+// explicitJobFields returns the clearable struct fields present in an update
+// body, in a stable order.
+func explicitJobFields(body []byte) ([]string, error) {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, err
+	}
+	var fields []string
+	for key, field := range clearableJobFields {
+		if _, ok := keys[key]; ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func containsField(fields []string, name string) bool {
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Mirrors the update in UpdateJob so validation sees the job as it will be
+// stored: non-zero fields of update replace existing values, and explicit
+// fields are copied even when zero.
+func mergeJobUpdate(existing, update models.Job, explicit []string) models.Job {
+	merged := existing
+	src := reflect.ValueOf(update)
+	dst := reflect.ValueOf(&merged).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		if field := src.Field(i); !field.IsZero() {
+			dst.Field(i).Set(field)
+		}
+	}
+	for _, name := range explicit {
+		dst.FieldByName(name).Set(src.FieldByName(name))
+	}
+	return merged
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+)
+
+func TestMergeJobUpdateClearsExplicitFields(t *testing.T) {
+	runAt := time.Date(2026, 12, 1, 9, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := models.Job{
+		Name:       "report",
+		Schedule:   "0 9 * * *",
+		Schedules:  []string{"0 17 * * *"},
+		Exclusions: []models.ScheduleExclusion{{Cron: "* * * * 0"}},
+		EndsAt:     &endsAt,
+		MaxRuns:    5,
+		CalendarID: "cal-1",
+	}
+	request := models.Job{
+		Name:          "webhook",
+		Method:        "POST",
+		Headers:       map[string]string{"X-Team": "ops"},
+		Body:          `{"ping":true}`,
+		ContentType:   "application/json",
+		Assertions:    []models.Assertion{{Type: models.AssertionStatusCode, StatusCodes: []int{200}}},
+		SigningSecret: "webhook-key",
+		Auth:          &models.JobAuth{Type: models.AuthBearer, Token: `{{secret "token"}}`},
+		TLS:           &models.JobTLS{ServerName: "api.internal"},
+		Completion:    &models.JobCompletion{Mode: models.CompletionCallback},
+	}
+	oneOff := models.Job{Name: "once", RunAt: &runAt}
+
+	tests := []struct {
+		name     string
+		existing models.Job
+		body     string
+		check    func(models.Job) bool
+	}{
+		{
+			name:     "omitted fields are kept",
+			existing: existing,
+			body:     `{"name":"renamed"}`,
+			check: func(j models.Job) bool {
+				return j.Name == "renamed" && j.Schedule == "0 9 * * *" && j.EndsAt != nil && j.MaxRuns == 5 && j.CalendarID == "cal-1"
+			},
+		},
+		{
+			name:     "null window and zero run limit clear them",
+			existing: existing,
+			body:     `{"ends_at":null,"max_runs":0}`,
+			check:    func(j models.Job) bool { return j.EndsAt == nil && j.MaxRuns == 0 && j.Schedule == "0 9 * * *" },
+		},
+		{
+			name:     "one-off job becomes recurring",
+			existing: oneOff,
+			body:     `{"run_at":null,"schedule":"*/5 * * * *"}`,
+			check:    func(j models.Job) bool { return j.RunAt == nil && j.Schedule == "*/5 * * * *" },
+		},
+		{
+			name:     "recurring job becomes one-off",
+			existing: existing,
+			body:     `{"run_at":"2026-12-01T09:00:00Z","schedule":"","schedules":[],"exclusions":null}`,
+			check: func(j models.Job) bool {
+				return j.RunAt != nil && j.Schedule == "" && len(j.Schedules) == 0 && len(j.Exclusions) == 0
+			},
+		},
+		{
+			name:     "calendar can be detached",
+			existing: existing,
+			body:     `{"calendar_id":""}`,
+			check:    func(j models.Job) bool { return j.CalendarID == "" },
+		},
+		{
+			name:     "request fields can be cleared",
+			existing: request,
+			body:     `{"method":"GET","body":"","headers":{},"content_type":"","assertions":[],"signing_secret":"","auth":null,"tls":null,"completion":null}`,
+			check: func(j models.Job) bool {
+				return j.Method == "GET" && j.Body == "" && len(j.Headers) == 0 && j.ContentType == "" && len(j.Assertions) == 0 &&
+					j.SigningSecret == "" && j.Auth == nil && j.TLS == nil && j.Completion == nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explicit, err := explicitJobFields([]byte(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			update := decodeJob(t, tt.body)
+			if merged := mergeJobUpdate(tt.existing, update, explicit); !tt.check(merged) {
+				t.Errorf("unexpected merge result %+v", merged)
+			}
+		})
+	}
+}
+
+func TestExplicitJobFields(t *testing.T) {
+	got, err := explicitJobFields([]byte(`{"name":"x","run_at":null,"schedule":"","max_runs":0,"url":"https://example.com"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"MaxRuns", "RunAt", "Schedule"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("explicitJobFields = %v, want %v", got, want)
+	}
+	if _, err := explicitJobFields([]byte(`[1]`)); err == nil {
+		t.Error("expected an error for a non-object body")
+	}
+}
+
+func decodeJob(t *testing.T, body string) models.Job {
+	t.Helper()
+	var job models.Job
+	if err := json.Unmarshal([]byte(body), &job); err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestValidateScheduleWindow(t *testing.T) {
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		job     models.Job
+		wantErr bool
+	}{
+		{"recurring job", models.Job{Schedule: "0 9 * * *", EndsAt: &future, MaxRuns: 3}, false},
+		{"one-off job", models.Job{RunAt: &future}, false},
+		{"one-off in the past", models.Job{RunAt: &past}, true},
+		{"run_at with schedule", models.Job{RunAt: &future, Schedule: "0 9 * * *"}, true},
+		{"window ends before it starts", models.Job{Schedule: "0 9 * * *", StartsAt: &future, EndsAt: &past}, true},
+		{"negative max_runs", models.Job{Schedule: "0 9 * * *", MaxRuns: -1}, true},
+		{"heartbeat", models.Job{Type: models.JobTypeHeartbeat, Schedule: "*/5 * * * *"}, false},
+		{"heartbeat with ends_at", models.Job{Type: models.JobTypeHeartbeat, Schedule: "*/5 * * * *", EndsAt: &future}, true},
+		{"heartbeat with starts_at", models.Job{Type: models.JobTypeHeartbeat, Schedule: "*/5 * * * *", StartsAt: &past}, true},
+		{"heartbeat with max_runs", models.Job{Type: models.JobTypeHeartbeat, Schedule: "*/5 * * * *", MaxRuns: 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateScheduleWindow(&tt.job); (err != nil) != tt.wantErr {
+				t.Errorf("validateScheduleWindow() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,12 +1,17 @@
 package controllers
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/robfig/cron/v3"
 	"github.com/conan-flynn/cronnect/middleware"
 	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/queue"
 	"github.com/conan-flynn/cronnect/scheduler"
+	"github.com/conan-flynn/cronnect/worker"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -28,7 +33,10 @@ func (jc *JobController) GetJobs(c *gin.Context) {
 	}
 
 	var jobs []models.Job
-	jc.DB.Where("user_id = ?", userID).Preload("Executions").Find(&jobs)
+	jc.DB.Where("user_id = ?", userID).Preload("Executions").Preload("Dependencies").Find(&jobs)
+	for i := range jobs {
+		presentJob(&jobs[i])
+	}
 	c.IndentedJSON(http.StatusOK, jobs)
 }
 
@@ -42,7 +50,7 @@ func (jc *JobController) GetJob(c *gin.Context) {
 	jobID := c.Param("id")
 	
 	var job models.Job
-	if err := jc.DB.Where("id = ? AND user_id = ?", jobID, userID).Preload("Executions").First(&job).Error; err != nil {
+	if err := jc.DB.Where("id = ? AND user_id = ?", jobID, userID).Preload("Executions").Preload("Dependencies").First(&job).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
 		} else {
@@ -50,7 +58,8 @@ func (jc *JobController) GetJob(c *gin.Context) {
 		}
 		return
 	}
-	
+
+	presentJob(&job)
 	c.IndentedJSON(http.StatusOK, job)
 }
 
@@ -67,12 +76,31 @@ func (jc *JobController) CreateJob(c *gin.Context) {
 		return
 	}
 
-	if _, err := cron.ParseStandard(newJob.Schedule); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron schedule"})
-		return
+	if newJob.Schedule != "" || (len(newJob.Schedules) == 0 && len(newJob.Dependencies) == 0 && newJob.RunAt == nil) {
+		if _, err := scheduler.ParseSchedule(newJob.Schedule, time.UTC, ""); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron schedule"})
+			return
+		}
 	}
+
 	newJob.ID = uuid.NewString()
 	newJob.UserID = userID.(string)
+	if newJob.Timezone == "" {
+		newJob.Timezone = jc.userTimezone(newJob.UserID)
+	}
+	newJob.PingToken, newJob.HeartbeatState, newJob.LastPingAt = "", "", nil
+	newJob.Status, newJob.PausedAt, newJob.PausedUntil, newJob.PausedBy, newJob.PauseReason = "", nil, nil, "", ""
+	newJob.RunCount = 0
+	if newJob.Type == models.JobTypeHeartbeat {
+		newJob.PingToken = newPingToken()
+		newJob.HeartbeatState = models.HeartbeatNew
+	}
+
+	newJob.Method = strings.ToUpper(newJob.Method)
+	if err := jc.validateJob(userID.(string), &newJob); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	if err := jc.DB.Create(&newJob).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create job"})
@@ -80,7 +108,8 @@ func (jc *JobController) CreateJob(c *gin.Context) {
 	}
 	
 	scheduler.ReloadJobs()
-	
+
+	presentJob(&newJob)
 	c.IndentedJSON(http.StatusCreated, newJob)
 }
 
@@ -103,29 +132,93 @@ func (jc *JobController) UpdateJob(c *gin.Context) {
 		return
 	}
 
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job data"})
+		return
+	}
 	var updateData models.Job
-	if err := c.BindJSON(&updateData); err != nil {
+	if err := json.Unmarshal(body, &updateData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job data"})
+		return
+	}
+	explicit, err := explicitJobFields(body)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job data"})
 		return
 	}
 
 	if updateData.Schedule != "" {
-		if _, err := cron.ParseStandard(updateData.Schedule); err != nil {
+		if _, err := scheduler.ParseSchedule(updateData.Schedule, time.UTC, ""); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron schedule"})
 			return
 		}
 	}
 
+	updateData.Method = strings.ToUpper(updateData.Method)
+	updateData.PingToken, updateData.HeartbeatState, updateData.LastPingAt = "", "", nil
+	updateData.Status, updateData.PausedAt, updateData.PausedUntil, updateData.PausedBy, updateData.PauseReason = "", nil, nil, "", ""
+	updateData.RunCount = 0
+	reschedules := updateData.Recurring() || updateData.RunAt != nil || updateData.EndsAt != nil || updateData.MaxRuns != 0 ||
+		containsField(explicit, "EndsAt") || containsField(explicit, "MaxRuns")
+	if existingJob.Status == models.JobStatusCompleted && reschedules {
+		updateData.Status = models.JobStatusActive
+	}
+	if updateData.Type == models.JobTypeHeartbeat && existingJob.PingToken == "" {
+		updateData.PingToken = newPingToken()
+		updateData.HeartbeatState = models.HeartbeatNew
+	}
+	merged := mergeJobUpdate(existingJob, updateData, explicit)
+	if err := jc.validateJob(userID.(string), &merged); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	updateData.UserID = existingJob.UserID
 	updateData.ID = existingJob.ID
 
-	if err := jc.DB.Model(&existingJob).Updates(updateData).Error; err != nil {
+	err = jc.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&existingJob).Omit("Dependencies").Updates(updateData).Error; err != nil {
+			return err
+		}
+		// Updates skips zero values, so fields the request set to null or
+		// empty are written separately to clear them.
+		if len(explicit) > 0 {
+			if err := tx.Model(&existingJob).Select(explicit).Updates(updateData).Error; err != nil {
+				return err
+			}
+		}
+		if updateData.Status == models.JobStatusActive {
+			if err := tx.Model(&existingJob).UpdateColumn("run_count", 0).Error; err != nil {
+				return err
+			}
+		}
+		if updateData.Dependencies == nil {
+			return nil
+		}
+		if err := tx.Where("downstream_job_id = ?", existingJob.ID).Delete(&models.JobDependency{}).Error; err != nil {
+			return err
+		}
+		for i := range updateData.Dependencies {
+			updateData.Dependencies[i].ID = 0
+			updateData.Dependencies[i].DownstreamJobID = existingJob.ID
+		}
+		if len(updateData.Dependencies) > 0 {
+			if err := tx.Create(&updateData.Dependencies).Error; err != nil {
+				return err
+			}
+		}
+		existingJob.Dependencies = updateData.Dependencies
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update job"})
 		return
 	}
 	
 	scheduler.ReloadJobs()
-	
+
+	presentJob(&existingJob)
 	c.IndentedJSON(http.StatusOK, existingJob)
 }
 
@@ -158,6 +251,375 @@ func (jc *JobController) DeleteJob(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "job deleted successfully"})
 }
 
+type runJobRequest struct {
+	Headers map[string]string `json:"headers"`
+	Body    *string           `json:"body"`
+}
+
+func (jc *JobController) RunJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	jobID := c.Param("id")
+
+	var job models.Job
+	if err := jc.DB.Where("id = ? AND user_id = ?", jobID, userID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve job"})
+		}
+		return
+	}
+
+	if job.Type == models.JobTypeHeartbeat {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "heartbeat jobs cannot be run manually"})
+		return
+	}
+
+	var req runJobRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid run options"})
+			return
+		}
+	}
+
+	stored := job
+	if len(req.Headers) > 0 || req.Body != nil {
+		headers := make(map[string]string, len(job.Headers)+len(req.Headers))
+		for key, value := range job.Headers {
+			headers[key] = value
+		}
+		for key, value := range req.Headers {
+			headers[key] = value
+		}
+		job.Headers = headers
+		if req.Body != nil {
+			job.Body = *req.Body
+		}
+		if err := jc.validateJob(userID.(string), &job); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	executionID, err := scheduler.RunJob(&job, queue.PublishOptions{
+		Trigger:     models.TriggerManual,
+		TriggeredBy: userID.(string),
+	})
+	if err != nil {
+		switch err {
+		case scheduler.ErrRateLimited:
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+		case queue.ErrJobPending:
+			c.JSON(http.StatusConflict, gin.H{"error": "job already has a pending execution"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to queue job"})
+		}
+		return
+	}
+
+	presentJob(&stored)
+	c.IndentedJSON(http.StatusAccepted, gin.H{"execution_id": executionID, "job": stored})
+}
+
+type pauseJobRequest struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+func (jc *JobController) PauseJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	jobID := c.Param("id")
+
+	var job models.Job
+	if err := jc.DB.Where("id = ? AND user_id = ?", jobID, userID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve job"})
+		}
+		return
+	}
+
+	if job.Status != models.JobStatusActive {
+		c.JSON(http.StatusConflict, gin.H{"error": "job is not active"})
+		return
+	}
+
+	var req pauseJobRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pause data"})
+			return
+		}
+	}
+
+	if len(req.Reason) > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason must be at most 500 characters"})
+		return
+	}
+	if req.Until != nil && !req.Until.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+		return
+	}
+
+	now := time.Now()
+	err := jc.DB.Model(&job).Updates(map[string]interface{}{
+		"status":       models.JobStatusPaused,
+		"paused_at":    now,
+		"paused_until": req.Until,
+		"paused_by":    userID.(string),
+		"pause_reason": req.Reason,
+	}).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to pause job"})
+		return
+	}
+
+	scheduler.ReloadJobs()
+
+	presentJob(&job)
+	c.IndentedJSON(http.StatusOK, job)
+}
+
+func (jc *JobController) ResumeJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	jobID := c.Param("id")
+
+	var job models.Job
+	if err := jc.DB.Where("id = ? AND user_id = ?", jobID, userID).First(&job).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve job"})
+		}
+		return
+	}
+
+	if job.Status != models.JobStatusPaused {
+		c.JSON(http.StatusConflict, gin.H{"error": "job is not paused"})
+		return
+	}
+
+	if err := scheduler.ResumeJob(&job); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to resume job"})
+		return
+	}
+
+	scheduler.ReloadJobs()
+
+	presentJob(&job)
+	c.IndentedJSON(http.StatusOK, job)
+}
+
+func (jc *JobController) TestJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var job models.Job
+	if err := c.BindJSON(&job); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job data"})
+		return
+	}
+
+	if job.Type == models.JobTypeHeartbeat {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "heartbeat jobs cannot be tested"})
+		return
+	}
+
+	job.ID = ""
+	job.UserID = userID.(string)
+	job.Method = strings.ToUpper(job.Method)
+	job.Dependencies = nil
+	if err := jc.validateJob(userID.(string), &job); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rateLimiter := middleware.NewRateLimiter()
+	allowed, _, _, err := rateLimiter.CheckRateLimit(job.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check rate limit"})
+		return
+	} else if !allowed {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+		return
+	}
+	if err := rateLimiter.RecordPing(job.UserID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record rate limit usage"})
+		return
+	}
+
+	result := worker.DryRun(&job)
+	c.IndentedJSON(http.StatusOK, gin.H{
+		"status":            result.Status,
+		"response_code":     result.ResponseCode,
+		"error_message":     result.ErrorMessage,
+		"latency_ms":        result.LatencyMs,
+		"response_headers":  result.ResponseHeaders,
+		"response_body":     result.ResponseBody,
+		"remote_ip":         result.RemoteIP,
+		"assertion_results": result.AssertionResults,
+		"cert_expires_at":   result.CertExpiresAt,
+		"step_results":      result.StepResults,
+		"variables":         result.Variables,
+	})
+}
+
+func (jc *JobController) GetCertificates(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	withinDays := -1
+	if raw := c.Query("expiring_within"); raw != "" {
+		days, err := strconv.Atoi(raw)
+		if err != nil || days < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expiring_within must be a non-negative number of days"})
+			return
+		}
+		withinDays = days
+	}
+
+	var executions []models.JobExecution
+	err := jc.DB.Raw(`
+		SELECT DISTINCT ON (e.job_id) e.*
+		FROM job_executions e
+		JOIN jobs j ON j.id = e.job_id
+		WHERE j.user_id = ? AND e.cert_expires_at IS NOT NULL
+		ORDER BY e.job_id, e.started_at DESC`, userID).Scan(&executions).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve certificates"})
+		return
+	}
+
+	var jobs []models.Job
+	jc.DB.Where("user_id = ?", userID).Find(&jobs)
+	jobsByID := make(map[string]models.Job, len(jobs))
+	for _, job := range jobs {
+		jobsByID[job.ID] = job
+	}
+
+	certificates := []gin.H{}
+	for _, execution := range executions {
+		daysRemaining := int(time.Until(*execution.CertExpiresAt).Hours() / 24)
+		if withinDays >= 0 && daysRemaining > withinDays {
+			continue
+		}
+		job := jobsByID[execution.JobID]
+		certificates = append(certificates, gin.H{
+			"job_id":         job.ID,
+			"job_name":       job.Name,
+			"url":            job.URL,
+			"expires_at":     execution.CertExpiresAt,
+			"days_remaining": daysRemaining,
+			"issuer":         execution.CertIssuer,
+			"sans":           execution.CertSANs,
+			"checked_at":     execution.StartedAt,
+			"execution_id":   execution.ID,
+			"status":         execution.Status,
+		})
+	}
+
+	c.IndentedJSON(http.StatusOK, certificates)
+}
+
+func (jc *JobController) PreviewSchedule(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	expressions := c.QueryArray("expression")
+	job := models.Job{ID: c.Query("job_id"), Timezone: c.Query("timezone")}
+	if len(expressions) == 0 && job.ID != "" {
+		if err := jc.DB.Where("id = ? AND user_id = ?", job.ID, userID).First(&job).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+			return
+		}
+		if !job.Recurring() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "job has no schedule"})
+			return
+		}
+		if tz := c.Query("timezone"); tz != "" {
+			job.Timezone = tz
+		}
+	} else {
+		if len(expressions) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expression is required"})
+			return
+		}
+		job.Schedule, job.Schedules = expressions[0], expressions[1:]
+	}
+	if job.Timezone == "" {
+		job.Timezone = jc.userTimezone(userID.(string))
+	}
+
+	count := 5
+	if raw := c.Query("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 50 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "count must be between 1 and 50"})
+			return
+		}
+		count = n
+	}
+
+	loc, err := scheduler.LoadLocation(job.Timezone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := scheduler.JobSchedule(&job)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cron schedule: " + err.Error()})
+		return
+	}
+
+	expanded := make([]string, 0, len(job.Expressions()))
+	for _, expr := range job.Expressions() {
+		e, _ := scheduler.ExpandHashes(expr, job.ID)
+		expanded = append(expanded, e)
+	}
+	description, _ := scheduler.DescribeJob(&job)
+
+	runs := scheduler.NextRuns(schedule, time.Now(), count)
+	for i := range runs {
+		runs[i] = runs[i].In(loc)
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{
+		"expressions": job.Expressions(),
+		"exclusions":  job.Exclusions,
+		"expanded":    expanded,
+		"timezone":    loc.String(),
+		"description": description,
+		"next_runs":   runs,
+	})
+}
+
 func (jc *JobController) GetRateLimit(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/secrets"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxSecretValueSize = 64 * 1024
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+type SecretController struct {
+	DB *gorm.DB
+}
+
+type secretRequest struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func NewSecretController(db *gorm.DB) *SecretController {
+	return &SecretController{DB: db}
+}
+
+func (sc *SecretController) GetSecrets(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var secretList []models.Secret
+	sc.DB.Where("user_id = ?", userID).Order("name").Find(&secretList)
+	c.IndentedJSON(http.StatusOK, secretList)
+}
+
+func (sc *SecretController) CreateSecret(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if !secrets.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "secrets store is not configured"})
+		return
+	}
+
+	var req secretRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid secret data"})
+		return
+	}
+
+	if !secretNamePattern.MatchString(req.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "secret name must be 1-64 letters, digits, '.', '_' or '-'"})
+		return
+	}
+	if req.Value == "" || len(req.Value) > maxSecretValueSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "secret value must be between 1 and 65536 bytes"})
+		return
+	}
+
+	var count int64
+	sc.DB.Model(&models.Secret{}).Where("user_id = ? AND name = ?", userID, req.Name).Count(&count)
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "secret already exists"})
+		return
+	}
+
+	ciphertext, err := secrets.Encrypt([]byte(req.Value), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt secret"})
+		return
+	}
+
+	secret := models.Secret{
+		ID:         uuid.NewString(),
+		UserID:     userID.(string),
+		Name:       req.Name,
+		Ciphertext: ciphertext,
+	}
+	if err := sc.DB.Create(&secret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create secret"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusCreated, secret)
+}
+
+func (sc *SecretController) UpdateSecret(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if !secrets.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "secrets store is not configured"})
+		return
+	}
+
+	var secret models.Secret
+	if err := sc.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve secret"})
+		}
+		return
+	}
+
+	var req secretRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid secret data"})
+		return
+	}
+
+	if req.Value == "" || len(req.Value) > maxSecretValueSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "secret value must be between 1 and 65536 bytes"})
+		return
+	}
+
+	ciphertext, err := secrets.Encrypt([]byte(req.Value), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt secret"})
+		return
+	}
+
+	secret.Ciphertext = ciphertext
+	if err := sc.DB.Save(&secret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update secret"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, secret)
+}
+
+func (sc *SecretController) DeleteSecret(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var secret models.Secret
+	if err := sc.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&secret).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "secret not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve secret"})
+		}
+		return
+	}
+
+	if err := sc.DB.Delete(&secret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "secret deleted successfully"})
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/scheduler"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type SettingsController struct {
+	DB *gorm.DB
+}
+
+type settingsRequest struct {
+	DefaultTimezone string `json:"default_timezone"`
+}
+
+func NewSettingsController(db *gorm.DB) *SettingsController {
+	return &SettingsController{DB: db}
+}
+
+func (sc *SettingsController) GetSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var user models.User
+	if err := sc.DB.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve settings"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"default_timezone": user.DefaultTimezone})
+}
+
+func (sc *SettingsController) UpdateSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req settingsRequest
+	if err := c.BindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid settings data"})
+		return
+	}
+
+	if _, err := scheduler.LoadLocation(req.DefaultTimezone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := sc.DB.Model(&models.User{}).Where("id = ?", userID).Update("default_timezone", req.DefaultTimezone).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update settings"})
+		return
+	}
+
+	c.IndentedJSON(http.StatusOK, gin.H{"default_timezone": req.DefaultTimezone})
+}
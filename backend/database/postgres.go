@@ -29,7 +29,7 @@ func Connect() *gorm.DB {
 		log.Fatal("failed to connect to database")
 	}
 
-	db.AutoMigrate(&models.User{}, &models.Job{}, &models.JobExecution{})
+	db.AutoMigrate(&models.User{}, &models.Job{}, &models.JobExecution{}, &models.Secret{}, &models.JobDependency{}, &models.Calendar{}, &models.CalendarDate{})
 	DB = db
 	return db
 }
@@ -0,0 +1,270 @@
+package ics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parses the all-day and timed VEVENTs of an iCalendar (RFC 5545) file into
+// the dates they cover. Simple DAILY, WEEKLY, MONTHLY and YEARLY recurrence
+// rules are expanded up to recurrenceHorizonYears from now; events with other
+// rules keep only their first occurrence and report the rule as unexpanded.
+
+const dateLayout = "20060102"
+
+const (
+	// maxEventDays bounds how many days a single occurrence may span.
+	maxEventDays = 366
+	// maxDates bounds how many dates a whole file may expand to.
+	maxDates               = 5000
+	recurrenceHorizonYears = 5
+)
+
+// now is replaced in tests to pin the recurrence horizon.
+var now = time.Now
+
+type Event struct {
+	Summary string
+	Dates   []time.Time
+	// UnexpandedRule holds an RRULE this package cannot expand; Dates then
+	// only cover the first occurrence.
+	UnexpandedRule string
+}
+
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var inEvent bool
+	var summary, start, end, rule string
+	var endIsDate bool
+	var exdates []string
+	total := 0
+
+	for _, line := range lines {
+		name, params, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			inEvent = true
+			summary, start, end, rule = "", "", "", ""
+			endIsDate, exdates = false, nil
+		case name == "END" && value == "VEVENT":
+			if !inEvent {
+				continue
+			}
+			inEvent = false
+			event, err := buildEvent(summary, start, end, endIsDate, rule, exdates, maxDates-total)
+			if err != nil {
+				return nil, err
+			}
+			total += len(event.Dates)
+			events = append(events, event)
+		case !inEvent:
+		case name == "SUMMARY":
+			summary = unescape(value)
+		case name == "DTSTART":
+			start = value
+		case name == "DTEND":
+			end, endIsDate = value, isDateValue(params, value)
+		case name == "RRULE":
+			rule = value
+		case name == "EXDATE":
+			exdates = append(exdates, strings.Split(value, ",")...)
+		}
+	}
+
+	if len(events) == 0 {
+		return nil, errors.New("no events found")
+	}
+	return events, nil
+}
+
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+func splitLine(line string) (name, params, value string, ok bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", "", false
+	}
+	name, params, _ = strings.Cut(head, ";")
+	return strings.ToUpper(name), strings.ToUpper(params), strings.TrimSpace(value), true
+}
+
+func isDateValue(params, value string) bool {
+	if strings.Contains(params, "VALUE=DATE-TIME") {
+		return false
+	}
+	return strings.Contains(params, "VALUE=DATE") || !strings.Contains(value, "T")
+}
+
+// buildEvent expands an event into the dates it covers, failing once more
+// than budget dates would be produced.
+func buildEvent(summary, start, end string, endIsDate bool, rule string, exdates []string, budget int) (Event, error) {
+	if len(start) < len(dateLayout) {
+		return Event{}, fmt.Errorf("event %q has an invalid DTSTART", summary)
+	}
+	first, err := time.Parse(dateLayout, start[:len(dateLayout)])
+	if err != nil {
+		return Event{}, fmt.Errorf("event %q has an invalid DTSTART: %w", summary, err)
+	}
+
+	last := first
+	if len(end) >= len(dateLayout) {
+		endDate, err := time.Parse(dateLayout, end[:len(dateLayout)])
+		if err != nil {
+			return Event{}, fmt.Errorf("event %q has an invalid DTEND: %w", summary, err)
+		}
+		// All-day DTEND is exclusive, as is a timed end at midnight.
+		if endIsDate || strings.HasSuffix(strings.TrimSuffix(end, "Z"), "T000000") {
+			endDate = endDate.AddDate(0, 0, -1)
+		}
+		if endDate.After(last) {
+			last = endDate
+		}
+	}
+	span := int(last.Sub(first).Hours() / 24)
+	if span >= maxEventDays {
+		return Event{}, fmt.Errorf("event %q spans more than %d days", summary, maxEventDays)
+	}
+
+	event := Event{Summary: summary}
+	starts := []time.Time{first}
+	if rule != "" {
+		expanded, ok, err := expandRule(first, rule, budget)
+		if err != nil {
+			return Event{}, fmt.Errorf("event %q: %w", summary, err)
+		}
+		if ok {
+			starts = expanded
+		} else {
+			event.UnexpandedRule = rule
+		}
+	}
+
+	excluded := make(map[string]bool, len(exdates))
+	for _, exdate := range exdates {
+		if exdate = strings.TrimSpace(exdate); len(exdate) >= len(dateLayout) {
+			excluded[exdate[:len(dateLayout)]] = true
+		}
+	}
+	for _, occurrence := range starts {
+		if excluded[occurrence.Format(dateLayout)] {
+			continue
+		}
+		for i := 0; i <= span; i++ {
+			if len(event.Dates) == budget {
+				return Event{}, fmt.Errorf("calendar expands to more than %d dates", maxDates)
+			}
+			event.Dates = append(event.Dates, occurrence.AddDate(0, 0, i))
+		}
+	}
+	return event, nil
+}
+
+// expandRule returns the start dates of an RRULE until its COUNT, UNTIL or the
+// recurrence horizon. ok is false for rules that use parts it does not
+// support, such as BYDAY.
+func expandRule(first time.Time, rule string, budget int) (starts []time.Time, ok bool, err error) {
+	var freq string
+	var until time.Time
+	interval, count := 1, 0
+	for _, part := range strings.Split(rule, ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq = strings.ToUpper(value)
+		case "INTERVAL":
+			if interval, err = strconv.Atoi(value); err != nil || interval < 1 {
+				return nil, false, fmt.Errorf("invalid RRULE INTERVAL %q", value)
+			}
+		case "COUNT":
+			if count, err = strconv.Atoi(value); err != nil || count < 1 {
+				return nil, false, fmt.Errorf("invalid RRULE COUNT %q", value)
+			}
+		case "UNTIL":
+			if len(value) >= len(dateLayout) {
+				until, err = time.Parse(dateLayout, value[:len(dateLayout)])
+			}
+			if len(value) < len(dateLayout) || err != nil {
+				return nil, false, fmt.Errorf("invalid RRULE UNTIL %q", value)
+			}
+		case "WKST":
+			// Only matters for BYDAY, which is not expanded.
+		case "BYMONTH":
+			// A BYMONTH or BYMONTHDAY that repeats DTSTART changes nothing.
+			if value != strconv.Itoa(int(first.Month())) {
+				return nil, false, nil
+			}
+		case "BYMONTHDAY":
+			if value != strconv.Itoa(first.Day()) {
+				return nil, false, nil
+			}
+		default:
+			return nil, false, nil
+		}
+	}
+
+	var years, months, days int
+	switch freq {
+	case "DAILY":
+		days = interval
+	case "WEEKLY":
+		days = 7 * interval
+	case "MONTHLY":
+		months = interval
+	case "YEARLY":
+		years = interval
+	default:
+		return nil, false, nil
+	}
+
+	horizon := now().UTC().AddDate(recurrenceHorizonYears, 0, 0)
+	if !until.IsZero() && until.Before(horizon) {
+		horizon = until
+	}
+	for i := 0; count == 0 || len(starts) < count; i++ {
+		day := time.Date(first.Year()+i*years, first.Month()+time.Month(i*months), first.Day()+i*days, 0, 0, 0, 0, time.UTC)
+		if day.After(horizon) {
+			break
+		}
+		// Monthly and yearly rules skip months without the start day, such
+		// as the 31st or 29 February.
+		if days == 0 && day.Day() != first.Day() {
+			continue
+		}
+		if len(starts) == budget {
+			return nil, false, fmt.Errorf("calendar expands to more than %d dates", maxDates)
+		}
+		starts = append(starts, day)
+	}
+	return starts, true, nil
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(value)
+}
@@ -0,0 +1,195 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func dates(event Event) []string {
+	formatted := make([]string, len(event.Dates))
+	for i, day := range event.Dates {
+		formatted[i] = day.Format("2006-01-02")
+	}
+	return formatted
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		summary string
+		want    []string
+	}{
+		{
+			name:    "all-day event with exclusive DTEND",
+			input:   "BEGIN:VEVENT\nSUMMARY:New Year's Day\nDTSTART;VALUE=DATE:20270101\nDTEND;VALUE=DATE:20270102\nEND:VEVENT",
+			summary: "New Year's Day",
+			want:    []string{"2027-01-01"},
+		},
+		{
+			name:    "multi-day all-day event",
+			input:   "BEGIN:VEVENT\nSUMMARY:Christmas break\nDTSTART;VALUE=DATE:20261224\nDTEND;VALUE=DATE:20261227\nEND:VEVENT",
+			summary: "Christmas break",
+			want:    []string{"2026-12-24", "2026-12-25", "2026-12-26"},
+		},
+		{
+			name:    "all-day event without DTEND",
+			input:   "BEGIN:VEVENT\nSUMMARY:Boxing Day\nDTSTART;VALUE=DATE:20261226\nEND:VEVENT",
+			summary: "Boxing Day",
+			want:    []string{"2026-12-26"},
+		},
+		{
+			name:    "date value without VALUE parameter",
+			input:   "BEGIN:VEVENT\nSUMMARY:Holiday\nDTSTART:20260704\nDTEND:20260705\nEND:VEVENT",
+			summary: "Holiday",
+			want:    []string{"2026-07-04"},
+		},
+		{
+			name:    "timed event ending the same day",
+			input:   "BEGIN:VEVENT\nSUMMARY:Maintenance\nDTSTART:20260301T220000Z\nDTEND:20260301T235900Z\nEND:VEVENT",
+			summary: "Maintenance",
+			want:    []string{"2026-03-01"},
+		},
+		{
+			name:    "timed event ending at midnight",
+			input:   "BEGIN:VEVENT\nSUMMARY:Freeze\nDTSTART:20260301T090000\nDTEND:20260303T000000\nEND:VEVENT",
+			summary: "Freeze",
+			want:    []string{"2026-03-01", "2026-03-02"},
+		},
+		{
+			name:    "CRLF line endings, folded and escaped summary",
+			input:   "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nSUMMARY:Founders\\, Day\r\n  and party\r\nDTSTART;VALUE=DATE:20260915\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+			summary: "Founders, Day and party",
+			want:    []string{"2026-09-15"},
+		},
+		{
+			name:    "properties outside events are ignored",
+			input:   "BEGIN:VCALENDAR\nSUMMARY:Calendar name\nDTSTART:20260101\nBEGIN:VEVENT\nSUMMARY:Labour Day\nDTSTART;VALUE=DATE:20260501\nEND:VEVENT\nEND:VCALENDAR",
+			summary: "Labour Day",
+			want:    []string{"2026-05-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("parsed %d events, want 1", len(events))
+			}
+			if events[0].Summary != tt.summary {
+				t.Errorf("summary = %q, want %q", events[0].Summary, tt.summary)
+			}
+			if got := dates(events[0]); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("dates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMultipleEvents(t *testing.T) {
+	input := `BEGIN:VCALENDAR
+BEGIN:VEVENT
+SUMMARY:A
+DTSTART;VALUE=DATE:20260101
+END:VEVENT
+BEGIN:VEVENT
+SUMMARY:B
+DTSTART;VALUE=DATE:20260102
+END:VEVENT
+END:VCALENDAR`
+	events, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Summary != "A" || events[1].Summary != "B" {
+		t.Errorf("events = %+v", events)
+	}
+	if !events[1].Dates[0].Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("second event date = %s", events[1].Dates[0])
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	defer func(restore func() time.Time) { now = restore }(now)
+	now = func() time.Time { return time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name       string
+		input      string
+		want       []string
+		unexpanded bool
+	}{
+		{
+			name:  "yearly rule runs to the horizon",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261225\nRRULE:FREQ=YEARLY\nEND:VEVENT",
+			want:  []string{"2026-12-25", "2027-12-25", "2028-12-25", "2029-12-25", "2030-12-25"},
+		},
+		{
+			name:  "count and multi-day occurrences",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261224\nDTEND;VALUE=DATE:20261226\nRRULE:FREQ=YEARLY;COUNT=2\nEND:VEVENT",
+			want:  []string{"2026-12-24", "2026-12-25", "2027-12-24", "2027-12-25"},
+		},
+		{
+			name:  "weekly rule with until and exdate",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261102\nRRULE:FREQ=WEEKLY;INTERVAL=2;UNTIL=20261214T000000Z\nEXDATE;VALUE=DATE:20261116\nEND:VEVENT",
+			want:  []string{"2026-11-02", "2026-11-30", "2026-12-14"},
+		},
+		{
+			name:  "monthly rule skips months without the day",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270131\nRRULE:FREQ=MONTHLY;COUNT=3\nEND:VEVENT",
+			want:  []string{"2027-01-31", "2027-03-31", "2027-05-31"},
+		},
+		{
+			name:  "redundant BYMONTH and BYMONTHDAY",
+			input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101\nRRULE:FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=1;COUNT=2\nEND:VEVENT",
+			want:  []string{"2027-01-01", "2028-01-01"},
+		},
+		{
+			name:       "unsupported rule keeps the first occurrence",
+			input:      "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20261126\nRRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH\nEND:VEVENT",
+			want:       []string{"2026-11-26"},
+			unexpanded: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := dates(events[0]); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("dates = %v, want %v", got, tt.want)
+			}
+			if unexpanded := events[0].UnexpandedRule != ""; unexpanded != tt.unexpanded {
+				t.Errorf("unexpanded = %v, want %v", unexpanded, tt.unexpanded)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"no events", "BEGIN:VCALENDAR\nEND:VCALENDAR"},
+		{"missing DTSTART", "BEGIN:VEVENT\nSUMMARY:x\nEND:VEVENT"},
+		{"invalid DTSTART", "BEGIN:VEVENT\nDTSTART:2026-01-01\nEND:VEVENT"},
+		{"invalid DTEND", "BEGIN:VEVENT\nDTSTART:20260101\nDTEND:2026XX01\nEND:VEVENT"},
+		{"event spans too long", "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20260101\nDTEND;VALUE=DATE:20280101\nEND:VEVENT"},
+		{"invalid RRULE count", "BEGIN:VEVENT\nDTSTART:20260101\nRRULE:FREQ=DAILY;COUNT=x\nEND:VEVENT"},
+		{"too many dates", "BEGIN:VEVENT\nDTSTART:20000101\nRRULE:FREQ=DAILY\nEND:VEVENT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if events, err := Parse(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Parse succeeded with %+v, want error", events)
+			}
+		})
+	}
+}
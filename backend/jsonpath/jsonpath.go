@@ -0,0 +1,139 @@
+package jsonpath
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Supports the subset of JSONPath used by job assertions and extractions:
+// $.field, $.nested.field, $.list[0], $['quoted key'] and combinations thereof.
+
+type segment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func Validate(path string) error {
+	_, err := parse(path)
+	return err
+}
+
+func Lookup(data []byte, path string) (interface{}, error) {
+	segments, err := parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers stay json.Number so large integers keep their exact digits.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("response is not valid JSON: unexpected data after top-level value")
+	}
+
+	current := doc
+	for _, seg := range segments {
+		if seg.isIndex {
+			list, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("cannot index non-array with [%d]", seg.index)
+			}
+			idx := seg.index
+			if idx < 0 {
+				idx += len(list)
+			}
+			if idx < 0 || idx >= len(list) {
+				return nil, fmt.Errorf("index [%d] out of range", seg.index)
+			}
+			current = list[idx]
+			continue
+		}
+
+		obj, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("cannot read key %q from non-object", seg.key)
+		}
+		value, ok := obj[seg.key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", seg.key)
+		}
+		current = value
+	}
+
+	return current, nil
+}
+
+func LookupString(data []byte, path string) (string, error) {
+	value, err := Lookup(data, path)
+	if err != nil {
+		return "", err
+	}
+	return Stringify(value), nil
+}
+
+func Stringify(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(encoded)
+}
+
+func parse(path string) ([]segment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("jsonpath must start with $")
+	}
+
+	var segments []segment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end == -1 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in jsonpath %q", path)
+			}
+			segments = append(segments, segment{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("unterminated [ in jsonpath %q", path)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				segments = append(segments, segment{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			idx, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid index %q in jsonpath %q", inner, path)
+			}
+			segments = append(segments, segment{index: idx, isIndex: true})
+		default:
+			return nil, fmt.Errorf("unexpected character %q in jsonpath %q", rest[0], path)
+		}
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,95 @@
+package jsonpath
+
+import "testing"
+
+const document = `{
+	"status": "done",
+	"count": 42,
+	"ratio": 0.5,
+	"big": 9007199254740993,
+	"exp": 1e22,
+	"ok": true,
+	"missing": null,
+	"data": {"items": [{"id": "a"}, {"id": "b", "tags": ["x", "y"]}]},
+	"odd key": {"with.dot": "v"}
+}`
+
+func TestLookupString(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"$.status", "done"},
+		{"$.count", "42"},
+		{"$.ratio", "0.5"},
+		{"$.big", "9007199254740993"},
+		{"$.exp", "1e22"},
+		{"$.ok", "true"},
+		{"$.missing", "null"},
+		{"$.data.items[0].id", "a"},
+		{"$.data.items[1].tags[1]", "y"},
+		{"$.data.items[-1].id", "b"},
+		{"$['odd key']['with.dot']", "v"},
+		{`$["status"]`, "done"},
+		{"$.data.items[1].tags", `["x","y"]`},
+		{"$", `{"big":9007199254740993,"count":42,"data":{"items":[{"id":"a"},{"id":"b","tags":["x","y"]}]},"exp":1e22,"missing":null,"odd key":{"with.dot":"v"},"ok":true,"ratio":0.5,"status":"done"}`},
+	}
+	for _, tt := range tests {
+		got, err := LookupString([]byte(document), tt.path)
+		if err != nil {
+			t.Errorf("LookupString(%q) error: %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("LookupString(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLookupErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		path string
+	}{
+		{"missing key", document, "$.nope"},
+		{"index out of range", document, "$.data.items[2]"},
+		{"negative index out of range", document, "$.data.items[-3]"},
+		{"index into object", document, "$.data[0]"},
+		{"key on array", document, "$.data.items.id"},
+		{"key on scalar", document, "$.status.length"},
+		{"invalid JSON", `{"status":`, "$.status"},
+		{"trailing data", `{"status":"done"} {}`, "$.status"},
+		{"invalid path", document, "status"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if value, err := Lookup([]byte(tt.data), tt.path); err == nil {
+				t.Errorf("Lookup(%q) = %v, want error", tt.path, value)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+	}{
+		{"$", true},
+		{"$.a.b", true},
+		{"$.a[0].b", true},
+		{"$['a b'][1]", true},
+		{"a.b", false},
+		{"$.", false},
+		{"$..a", false},
+		{"$.a[", false},
+		{"$.a[x]", false},
+		{"$a", false},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.path); (err == nil) != tt.valid {
+			t.Errorf("Validate(%q) error = %v, want valid %v", tt.path, err, tt.valid)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strconv"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/conan-flynn/cronnect/auth"
 	"github.com/conan-flynn/cronnect/database"
@@ -13,6 +14,7 @@ import (
 	"github.com/conan-flynn/cronnect/queue"
 	"github.com/conan-flynn/cronnect/routes"
 	"github.com/conan-flynn/cronnect/scheduler"
+	"github.com/conan-flynn/cronnect/secrets"
 	"github.com/conan-flynn/cronnect/worker"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
@@ -25,6 +27,7 @@ func main() {
 	godotenv.Load()
 	
 	auth.InitOAuth()
+	secrets.InitEncryption()
 	
 	sessionSecret := os.Getenv("SESSION_SECRET")
 	if sessionSecret == "" {
@@ -54,7 +57,7 @@ func main() {
 		panic("failed to connect to database")
 	}
 	database.DB = db
-	db.AutoMigrate(&models.User{}, &models.Job{}, &models.JobExecution{})
+	db.AutoMigrate(&models.User{}, &models.Job{}, &models.JobExecution{}, &models.Secret{}, &models.JobDependency{}, &models.Calendar{}, &models.CalendarDate{})
 
 	database.ConnectRedis()
 
@@ -62,6 +65,7 @@ func main() {
 
 	queueService := queue.NewQueueService()
 	go queueService.ProcessRetryQueue()
+	go queueService.ProcessAsyncDeadlines()
 
 	workerCount := getWorkerCount()
 	log.Printf("Starting %d workers", workerCount)
@@ -0,0 +1,27 @@
+package models
+
+const (
+	AssertionStatusCode   = "status_code"
+	AssertionBodyContains = "body_contains"
+	AssertionBodyRegex    = "body_regex"
+	AssertionJSONPath     = "json_path"
+	AssertionHeader       = "header"
+	AssertionResponseTime = "response_time"
+)
+
+type Assertion struct {
+	Type        string `json:"type"`
+	Property    string `json:"property,omitempty"`
+	Value       string `json:"value,omitempty"`
+	StatusCodes []int  `json:"status_codes,omitempty"`
+	MaxMs       int    `json:"max_ms,omitempty"`
+}
+
+type AssertionResult struct {
+	Type     string `json:"type"`
+	Property string `json:"property,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Passed   bool   `json:"passed"`
+	Message  string `json:"message,omitempty"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const CalendarDateLayout = "2006-01-02"
+
+type Calendar struct {
+	ID        string         `gorm:"primaryKey" json:"id"`
+	UserID    string         `gorm:"not null;index" json:"user_id"`
+	Name      string         `gorm:"size:100;not null" json:"name"`
+	Shared    bool           `gorm:"default:false" json:"shared"`
+	Dates     []CalendarDate `gorm:"foreignKey:CalendarID;constraint:OnDelete:CASCADE" json:"dates"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	User      User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+type CalendarDate struct {
+	ID         uint   `gorm:"primaryKey" json:"-"`
+	CalendarID string `gorm:"not null;uniqueIndex:idx_calendar_dates_calendar_date" json:"-"`
+	Date       string `gorm:"size:10;not null;uniqueIndex:idx_calendar_dates_calendar_date" json:"date"`
+	Name       string `gorm:"size:255" json:"name,omitempty"`
+}
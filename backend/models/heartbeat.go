@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	HeartbeatNew  = "new"
+	HeartbeatUp   = "up"
+	HeartbeatLate = "late"
+	HeartbeatDown = "down"
+)
+
+const (
+	PingStart   = "start"
+	PingSuccess = "success"
+	PingFail    = "fail"
+)
+
+const (
+	DefaultHeartbeatGraceSeconds = 300
+	MaxHeartbeatGraceSeconds     = 7 * 24 * 3600
+)
+
+type HeartbeatConfig struct {
+	GraceSeconds int `json:"grace_seconds,omitempty"`
+}
+
+func ParseHeartbeatConfig(raw json.RawMessage) (*HeartbeatConfig, error) {
+	config := HeartbeatConfig{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &config); err != nil {
+			return nil, fmt.Errorf("invalid heartbeat config: %w", err)
+		}
+	}
+	if config.GraceSeconds < 0 || config.GraceSeconds > MaxHeartbeatGraceSeconds {
+		return nil, fmt.Errorf("config.grace_seconds must be between 1 and %d", MaxHeartbeatGraceSeconds)
+	}
+	if config.GraceSeconds == 0 {
+		config.GraceSeconds = DefaultHeartbeatGraceSeconds
+	}
+	return &config, nil
+}
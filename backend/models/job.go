@@ -1,13 +1,80 @@
 package models
 
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobTypeHTTP      = "http"
+	JobTypeTCP       = "tcp"
+	JobTypeScenario  = "scenario"
+	JobTypeHeartbeat = "heartbeat"
+)
+
+const (
+	TriggerSchedule   = "schedule"
+	TriggerDependency = "dependency"
+	TriggerManual     = "manual"
+	TriggerPing       = "ping"
+	TriggerHeartbeat  = "heartbeat"
+)
+
+const (
+	JobStatusActive    = "active"
+	JobStatusPaused    = "paused"
+	JobStatusCompleted = "completed"
+)
+
+const (
+	DefaultTimeoutSeconds   = 30
+	MaxTimeoutSeconds       = 300
+	DefaultMaxRedirects     = 10
+	DefaultMaxResponseBytes = 1 << 20
+	MaxResponseBytesLimit   = 10 << 20
+)
+
 type Job struct {
-	ID         string         `gorm:"primaryKey" json:"id"`
-	UserID     string         `gorm:"not null;index" json:"user_id"`
-	Name       string         `gorm:"size:100;not null" json:"name"`
-	URL        string         `gorm:"not null" json:"url"`
-	Method     string         `gorm:"size:10;default:GET" json:"method"`
-	Schedule   string         `gorm:"size:100;not null" json:"schedule"`
-	Status     string         `gorm:"size:20;default:active" json:"status"`
-	User       User           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
-	Executions []JobExecution `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE" json:"executions"`
+	ID                    string              `gorm:"primaryKey" json:"id"`
+	UserID                string              `gorm:"not null;index" json:"user_id"`
+	Name                  string              `gorm:"size:100;not null" json:"name"`
+	Type                  string              `gorm:"size:20;default:http" json:"type"`
+	Config                json.RawMessage     `gorm:"serializer:json" json:"config,omitempty"`
+	URL                   string              `gorm:"not null" json:"url"`
+	Method                string              `gorm:"size:10;default:GET" json:"method"`
+	Headers               map[string]string   `gorm:"serializer:json" json:"headers,omitempty"`
+	Body                  string              `gorm:"type:text" json:"body,omitempty"`
+	ContentType           string              `gorm:"size:100" json:"content_type,omitempty"`
+	Assertions            []Assertion         `gorm:"serializer:json" json:"assertions,omitempty"`
+	TimeoutSeconds        int                 `json:"timeout_seconds,omitempty"`
+	FollowRedirects       *bool               `gorm:"default:true" json:"follow_redirects,omitempty"`
+	MaxRedirects          int                 `json:"max_redirects,omitempty"`
+	MaxResponseBytes      int64               `json:"max_response_bytes,omitempty"`
+	SigningSecret         string              `gorm:"size:64" json:"signing_secret,omitempty"`
+	Auth                  *JobAuth            `gorm:"serializer:json" json:"auth,omitempty"`
+	TLS                   *JobTLS             `gorm:"serializer:json" json:"tls,omitempty"`
+	CertExpiryWarningDays int                 `json:"cert_expiry_warning_days,omitempty"`
+	Completion            *JobCompletion      `gorm:"serializer:json" json:"completion,omitempty"`
+	PingToken             string              `gorm:"size:64;index" json:"ping_token,omitempty"`
+	HeartbeatState        string              `gorm:"size:20" json:"heartbeat_state,omitempty"`
+	LastPingAt            *time.Time          `json:"last_ping_at,omitempty"`
+	Dependencies          []JobDependency     `gorm:"foreignKey:DownstreamJobID;constraint:OnDelete:CASCADE" json:"depends_on,omitempty"`
+	Schedule              string              `gorm:"size:100" json:"schedule"`
+	Schedules             []string            `gorm:"serializer:json" json:"schedules,omitempty"`
+	Exclusions            []ScheduleExclusion `gorm:"serializer:json" json:"exclusions,omitempty"`
+	Timezone              string              `gorm:"size:64" json:"timezone,omitempty"`
+	RunAt                 *time.Time          `json:"run_at,omitempty"`
+	StartsAt              *time.Time          `json:"starts_at,omitempty"`
+	EndsAt                *time.Time          `json:"ends_at,omitempty"`
+	MaxRuns               int                 `json:"max_runs,omitempty"`
+	RunCount              int                 `gorm:"default:0" json:"run_count"`
+	CalendarID            string              `gorm:"index" json:"calendar_id,omitempty"`
+	NextRunAt             *time.Time          `gorm:"-" json:"next_run_at,omitempty"`
+	Status                string              `gorm:"size:20;default:active" json:"status"`
+	PausedAt              *time.Time          `json:"paused_at,omitempty"`
+	PausedUntil           *time.Time          `gorm:"index" json:"paused_until,omitempty"`
+	PausedBy              string              `gorm:"size:64" json:"paused_by,omitempty"`
+	PauseReason           string              `gorm:"size:500" json:"pause_reason,omitempty"`
+	User                  User                `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Executions            []JobExecution      `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE" json:"executions"`
 }
@@ -0,0 +1,26 @@
+package models
+
+const (
+	AuthBasic                   = "basic"
+	AuthBearer                  = "bearer"
+	AuthOAuth2ClientCredentials = "oauth2_client_credentials"
+)
+
+type JobTLS struct {
+	ClientCertSecret string `json:"client_cert_secret,omitempty"`
+	ClientKeySecret  string `json:"client_key_secret,omitempty"`
+	CACertSecret     string `json:"ca_cert_secret,omitempty"`
+	ServerName       string `json:"server_name,omitempty"`
+}
+
+type JobAuth struct {
+	Type         string   `json:"type"`
+	Username     string   `json:"username,omitempty"`
+	Password     string   `json:"password,omitempty"`
+	Token        string   `json:"token,omitempty"`
+	TokenURL     string   `json:"token_url,omitempty"`
+	ClientID     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	Scopes       []string `json:"scopes,omitempty"`
+	Audience     string   `json:"audience,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+const (
+	CompletionCallback = "callback"
+	CompletionPoll     = "poll"
+
+	DefaultCompletionDeadlineSeconds = 3600
+	MaxCompletionDeadlineSeconds     = 7 * 24 * 3600
+	DefaultPollIntervalSeconds       = 60
+)
+
+type JobCompletion struct {
+	Mode                string   `json:"mode"`
+	DeadlineSeconds     int      `json:"deadline_seconds,omitempty"`
+	PollIntervalSeconds int      `json:"poll_interval_seconds,omitempty"`
+	StatusPath          string   `json:"status_path,omitempty"`
+	SuccessValues       []string `json:"success_values,omitempty"`
+	FailureValues       []string `json:"failure_values,omitempty"`
+}
+
+type AsyncPollState struct {
+	StatusURL string    `json:"status_url"`
+	Deadline  time.Time `json:"deadline"`
+}
+
+func (c *JobCompletion) Deadline() time.Duration {
+	seconds := DefaultCompletionDeadlineSeconds
+	if c != nil && c.DeadlineSeconds > 0 {
+		seconds = c.DeadlineSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (c *JobCompletion) PollInterval() time.Duration {
+	seconds := DefaultPollIntervalSeconds
+	if c != nil && c.PollIntervalSeconds > 0 {
+		seconds = c.PollIntervalSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,26 @@
+package models
+
+const (
+	DependencyOnSuccess = "success"
+	DependencyOnFailure = "failure"
+	DependencyAlways    = "always"
+)
+
+type JobDependency struct {
+	ID              uint   `gorm:"primaryKey" json:"-"`
+	DownstreamJobID string `gorm:"not null;index" json:"-"`
+	UpstreamJobID   string `gorm:"not null;index" json:"job_id"`
+	Condition       string `gorm:"size:20;default:success" json:"condition"`
+	PassResponse    bool   `json:"pass_response,omitempty"`
+	UpstreamJob     *Job   `gorm:"foreignKey:UpstreamJobID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+type UpstreamContext struct {
+	JobID        string            `json:"job_id"`
+	JobName      string            `json:"job_name"`
+	ExecutionID  string            `json:"execution_id"`
+	Status       string            `json:"status"`
+	ResponseCode int               `json:"response_code,omitempty"`
+	ResponseBody string            `json:"response_body,omitempty"`
+	Variables    map[string]string `json:"variables,omitempty"`
+}
@@ -3,10 +3,24 @@ package models
 import "time"
 
 type JobExecution struct {
-	ID           string     `gorm:"primaryKey" json:"id"`
-	JobID        string     `gorm:"index;not null" json:"job_id"`
-	StartedAt    time.Time  `gorm:"autoCreateTime" json:"started_at"`
-	FinishedAt   *time.Time `json:"finished_at,omitempty"`
-	Status       string     `gorm:"size:20;not null" json:"status"`
-	ResponseCode int        `json:"response_code"`
+	ID                  string            `gorm:"primaryKey" json:"id"`
+	JobID               string            `gorm:"index;not null" json:"job_id"`
+	StartedAt           time.Time         `gorm:"autoCreateTime" json:"started_at"`
+	FinishedAt          *time.Time        `json:"finished_at,omitempty"`
+	Status              string            `gorm:"size:20;not null" json:"status"`
+	Trigger             string            `gorm:"size:20;default:schedule" json:"trigger"`
+	TriggeredBy         string            `gorm:"size:64" json:"triggered_by,omitempty"`
+	UpstreamExecutionID string            `gorm:"index" json:"upstream_execution_id,omitempty"`
+	ResponseCode        int               `json:"response_code"`
+	ErrorMessage        string            `gorm:"type:text" json:"error_message,omitempty"`
+	LatencyMs           int64             `json:"latency_ms"`
+	ResponseBody        string            `gorm:"type:text" json:"response_body,omitempty"`
+	ResponseHeaders     map[string]string `gorm:"serializer:json" json:"response_headers,omitempty"`
+	RemoteIP            string            `gorm:"size:64" json:"remote_ip,omitempty"`
+	Attempt             int               `gorm:"default:1" json:"attempt"`
+	AssertionResults    []AssertionResult `gorm:"serializer:json" json:"assertion_results,omitempty"`
+	CertExpiresAt       *time.Time        `gorm:"index" json:"cert_expires_at,omitempty"`
+	CertIssuer          string            `json:"cert_issuer,omitempty"`
+	CertSANs            []string          `gorm:"serializer:json" json:"cert_sans,omitempty"`
+	StepResults         []StepResult      `gorm:"serializer:json" json:"step_results,omitempty"`
 }
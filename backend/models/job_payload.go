@@ -1,19 +1,39 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 
 type JobPayload struct {
-	JobID        string            `json:"job_id"`
-	Name         string            `json:"name"`
-	URL          string            `json:"url"`
-	Method       string            `json:"method"`
-	Headers      map[string]string `json:"headers,omitempty"`
-	Body         string            `json:"body,omitempty"`
-	ExecutionID  string            `json:"execution_id"`
-	ScheduledAt  time.Time         `json:"scheduled_at"`
-	MaxRetries   int               `json:"max_retries"`
-	RetryCount   int               `json:"retry_count"`
+	JobID                 string            `json:"job_id"`
+	UserID                string            `json:"user_id"`
+	Name                  string            `json:"name"`
+	Type                  string            `json:"type"`
+	Config                json.RawMessage   `json:"config,omitempty"`
+	URL                   string            `json:"url"`
+	Method                string            `json:"method"`
+	Headers               map[string]string `json:"headers,omitempty"`
+	Body                  string            `json:"body,omitempty"`
+	ContentType           string            `json:"content_type,omitempty"`
+	Assertions            []Assertion       `json:"assertions,omitempty"`
+	TimeoutSeconds        int               `json:"timeout_seconds,omitempty"`
+	FollowRedirects       *bool             `json:"follow_redirects,omitempty"`
+	MaxRedirects          int               `json:"max_redirects,omitempty"`
+	MaxResponseBytes      int64             `json:"max_response_bytes,omitempty"`
+	SigningSecret         string            `json:"signing_secret,omitempty"`
+	Auth                  *JobAuth          `json:"auth,omitempty"`
+	TLS                   *JobTLS           `json:"tls,omitempty"`
+	CertExpiryWarningDays int               `json:"cert_expiry_warning_days,omitempty"`
+	Completion            *JobCompletion    `json:"completion,omitempty"`
+	AsyncPoll             *AsyncPollState   `json:"async_poll,omitempty"`
+	Upstream              *UpstreamContext  `json:"upstream,omitempty"`
+	ExecutionID           string            `json:"execution_id"`
+	ScheduledAt           time.Time         `json:"scheduled_at"`
+	Timezone              string            `json:"timezone,omitempty"`
+	MaxRetries            int               `json:"max_retries"`
+	RetryCount            int               `json:"retry_count"`
 }
 
 
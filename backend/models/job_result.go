@@ -3,9 +3,21 @@ package models
 import "time"
 
 type JobResult struct {
-	ExecutionID  string    `json:"execution_id"`
-	Status       string    `json:"status"`
-	ResponseCode int       `json:"response_code,omitempty"`
-	ErrorMessage string    `json:"error_message,omitempty"`
-	CompletedAt  time.Time `json:"completed_at"`
+	ExecutionID      string            `json:"execution_id"`
+	Status           string            `json:"status"`
+	ResponseCode     int               `json:"response_code,omitempty"`
+	ErrorMessage     string            `json:"error_message,omitempty"`
+	CompletedAt      time.Time         `json:"completed_at"`
+	LatencyMs        int64             `json:"latency_ms"`
+	ResponseBody     string            `json:"response_body,omitempty"`
+	ResponseHeaders  map[string]string `json:"response_headers,omitempty"`
+	RemoteIP         string            `json:"remote_ip,omitempty"`
+	Attempt          int               `json:"attempt"`
+	AssertionResults []AssertionResult `json:"assertion_results,omitempty"`
+	CertExpiresAt    *time.Time        `json:"cert_expires_at,omitempty"`
+	CertIssuer       string            `json:"cert_issuer,omitempty"`
+	CertSANs         []string          `json:"cert_sans,omitempty"`
+	StepResults      []StepResult      `json:"step_results,omitempty"`
+	Variables        map[string]string `json:"variables,omitempty"`
+	AsyncPoll        *AsyncPollState   `json:"async_poll,omitempty"`
 }
@@ -0,0 +1,40 @@
+package models
+
+const (
+	ExtractJSONPath = "json_path"
+	ExtractRegex    = "regex"
+	ExtractHeader   = "header"
+)
+
+type ScenarioConfig struct {
+	Steps []ScenarioStep `json:"steps"`
+}
+
+type ScenarioStep struct {
+	Name        string            `json:"name"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+	Assertions  []Assertion       `json:"assertions,omitempty"`
+	Extract     []Extraction      `json:"extract,omitempty"`
+	AlwaysRun   bool              `json:"always_run,omitempty"`
+}
+
+type Extraction struct {
+	Var        string `json:"var"`
+	Source     string `json:"source"`
+	Expression string `json:"expression"`
+}
+
+type StepResult struct {
+	Name             string            `json:"name"`
+	Status           string            `json:"status"`
+	ResponseCode     int               `json:"response_code,omitempty"`
+	LatencyMs        int64             `json:"latency_ms"`
+	ErrorMessage     string            `json:"error_message,omitempty"`
+	ResponseBody     string            `json:"response_body,omitempty"`
+	AssertionResults []AssertionResult `json:"assertion_results,omitempty"`
+	Extracted        []string          `json:"extracted,omitempty"`
+}
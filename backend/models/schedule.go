@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ScheduleExclusion suppresses fire times matching a cron expression, falling
+// inside an absolute From/To window, or inside a daily StartTime-EndTime
+// window (HH:MM in the job's timezone, may wrap past midnight).
+type ScheduleExclusion struct {
+	Cron      string     `json:"cron,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	StartTime string     `json:"start_time,omitempty"`
+	EndTime   string     `json:"end_time,omitempty"`
+}
+
+func (j *Job) Recurring() bool {
+	return j.Schedule != "" || len(j.Schedules) > 0
+}
+
+func (j *Job) Expressions() []string {
+	var expressions []string
+	if j.Schedule != "" {
+		expressions = append(expressions, j.Schedule)
+	}
+	return append(expressions, j.Schedules...)
+}
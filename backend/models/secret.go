@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type Secret struct {
+	ID         string    `gorm:"primaryKey" json:"id"`
+	UserID     string    `gorm:"not null;uniqueIndex:idx_secrets_user_name" json:"user_id"`
+	Name       string    `gorm:"size:64;not null;uniqueIndex:idx_secrets_user_name" json:"name"`
+	Ciphertext []byte    `gorm:"not null" json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	User       User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
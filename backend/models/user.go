@@ -3,10 +3,11 @@ package models
 import "time"
 
 type User struct {
-	ID        string    `gorm:"primaryKey" json:"id"`
-	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
-	Name      string    `gorm:"size:255" json:"name"`
-	Provider  string    `gorm:"size:50;not null" json:"provider"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              string    `gorm:"primaryKey" json:"id"`
+	Email           string    `gorm:"uniqueIndex;not null" json:"email"`
+	Name            string    `gorm:"size:255" json:"name"`
+	Provider        string    `gorm:"size:50;not null" json:"provider"`
+	DefaultTimezone string    `gorm:"size:64" json:"default_timezone,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
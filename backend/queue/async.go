@@ -0,0 +1,161 @@
+package queue
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/redis/go-redis/v9"
+)
+
+const AsyncDeadlineQueue = "cronnect:async_deadlines"
+
+var (
+	ErrInvalidCallbackToken = errors.New("invalid or already used callback token")
+	ErrExecutionNotRunning  = errors.New("execution is not awaiting completion")
+)
+
+func callbackKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "callback:" + hex.EncodeToString(sum[:])
+}
+
+func (qs *QueueService) IssueCallbackToken(executionID string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := qs.client.Set(qs.ctx, callbackKey(token), executionID, ttl).Err(); err != nil {
+		return "", fmt.Errorf("failed to store callback token: %w", err)
+	}
+	return token, nil
+}
+
+func (qs *QueueService) RedeemCallbackToken(token string) (string, error) {
+	executionID, err := qs.client.GetDel(qs.ctx, callbackKey(token)).Result()
+	if err == redis.Nil {
+		return "", ErrInvalidCallbackToken
+	}
+	return executionID, err
+}
+
+func (qs *QueueService) awaitCompletion(payload *models.JobPayload, result *models.JobResult, execution *models.JobExecution) {
+	deadline := time.Now().Add(payload.Completion.Deadline())
+	if result.AsyncPoll != nil {
+		deadline = result.AsyncPoll.Deadline
+	}
+
+	qs.client.ZAdd(qs.ctx, AsyncDeadlineQueue, redis.Z{
+		Score:  float64(deadline.Unix()),
+		Member: execution.ID,
+	})
+	qs.client.Expire(qs.ctx, fmt.Sprintf("pending:%s", payload.JobID), time.Until(deadline))
+
+	if result.AsyncPoll != nil {
+		qs.schedulePoll(payload, result.AsyncPoll)
+	}
+
+	log.Printf("Job %s (execution %s) awaiting completion until %s", payload.Name, execution.ID, deadline.Format(time.RFC3339))
+}
+
+func (qs *QueueService) handlePollResult(payload *models.JobPayload, result *models.JobResult) error {
+	if result.Status == "running" {
+		if time.Now().Before(payload.AsyncPoll.Deadline) {
+			qs.schedulePoll(payload, payload.AsyncPoll)
+		}
+		return nil
+	}
+
+	err := qs.CompleteAsync(payload.ExecutionID, result.Status, result.ErrorMessage, result.ResponseCode)
+	if err == ErrExecutionNotRunning {
+		return nil
+	}
+	return err
+}
+
+func (qs *QueueService) schedulePoll(payload *models.JobPayload, state *models.AsyncPollState) {
+	poll := *payload
+	poll.AsyncPoll = state
+
+	if err := qs.enqueueDelayed(&poll, payload.Completion.PollInterval()); err != nil {
+		log.Printf("Failed to schedule status poll for job %s: %v", payload.Name, err)
+	}
+}
+
+func (qs *QueueService) CompleteAsync(executionID, status, message string, responseCode int) error {
+	var execution models.JobExecution
+	if err := database.DB.First(&execution, "id = ?", executionID).Error; err != nil {
+		return fmt.Errorf("failed to find execution record: %w", err)
+	}
+	if execution.Status != "running" {
+		return ErrExecutionNotRunning
+	}
+
+	var job models.Job
+	if err := database.DB.First(&job, "id = ?", execution.JobID).Error; err != nil {
+		return fmt.Errorf("failed to find job: %w", err)
+	}
+
+	now := time.Now()
+	execution.Status = status
+	execution.ErrorMessage = message
+	execution.FinishedAt = &now
+	if responseCode != 0 {
+		execution.ResponseCode = responseCode
+	}
+	saved, err := saveIfRunning(&execution)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		return ErrExecutionNotRunning
+	}
+
+	qs.client.ZRem(qs.ctx, AsyncDeadlineQueue, executionID)
+	qs.client.Del(qs.ctx, fmt.Sprintf("pending:%s", job.ID))
+	log.Printf("Job %s (execution %s) completed asynchronously with status %s", job.Name, executionID, status)
+
+	payload := &models.JobPayload{JobID: job.ID, Name: job.Name, ExecutionID: executionID}
+	qs.triggerDownstream(payload, &models.JobResult{
+		ExecutionID:  executionID,
+		Status:       status,
+		ResponseCode: execution.ResponseCode,
+		ErrorMessage: message,
+	})
+	return nil
+}
+
+func (qs *QueueService) ProcessAsyncDeadlines() {
+	for {
+		now := float64(time.Now().Unix())
+
+		expired, err := qs.client.ZRangeByScore(qs.ctx, AsyncDeadlineQueue, &redis.ZRangeBy{
+			Min: "0",
+			Max: fmt.Sprintf("%.0f", now),
+		}).Result()
+		if err != nil {
+			log.Printf("Error processing async deadlines: %v", err)
+			time.Sleep(10 * time.Second)
+			continue
+		}
+
+		for _, executionID := range expired {
+			qs.client.ZRem(qs.ctx, AsyncDeadlineQueue, executionID)
+			err := qs.CompleteAsync(executionID, "failed", "completion deadline exceeded", 0)
+			if err != nil && err != ErrExecutionNotRunning {
+				log.Printf("Failed to expire execution %s: %v", executionID, err)
+			}
+		}
+
+		time.Sleep(30 * time.Second)
+	}
+}
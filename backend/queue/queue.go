@@ -3,11 +3,13 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
 	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/middleware"
 	"github.com/conan-flynn/cronnect/models"
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
@@ -21,6 +23,14 @@ const (
 	DefaultMaxRetries = 3
 )
 
+var ErrJobPending = errors.New("job already has a pending execution")
+
+type PublishOptions struct {
+	Trigger     string
+	TriggeredBy string
+	Upstream    *models.UpstreamContext
+}
+
 type QueueService struct {
 	client *redis.Client
 	ctx    context.Context
@@ -35,6 +45,11 @@ func NewQueueService() *QueueService {
 
 
 func (qs *QueueService) PublishJob(job *models.Job) error {
+	_, err := qs.Publish(job, PublishOptions{Trigger: models.TriggerSchedule})
+	return err
+}
+
+func (qs *QueueService) Publish(job *models.Job, opts PublishOptions) (string, error) {
 	pendingKey := fmt.Sprintf("pending:%s", job.ID)
 	
 	exists, err := qs.client.Exists(qs.ctx, pendingKey).Result()
@@ -42,35 +57,32 @@ func (qs *QueueService) PublishJob(job *models.Job) error {
 		log.Printf("Error checking pending job: %v", err)
 	} else if exists > 0 {
 		log.Printf("Job %s already has pending execution, skipping", job.Name)
-		return nil
+		return "", ErrJobPending
 	}
 
 	executionID := uuid.NewString()
 	
 
 	execution := models.JobExecution{
-		ID:        executionID,
-		JobID:     job.ID,
-		StartedAt: time.Now(),
-		Status:    "queued",
+		ID:          executionID,
+		JobID:       job.ID,
+		StartedAt:   time.Now(),
+		Status:      "queued",
+		Trigger:     opts.Trigger,
+		TriggeredBy: opts.TriggeredBy,
+	}
+	if opts.Upstream != nil {
+		execution.UpstreamExecutionID = opts.Upstream.ExecutionID
 	}
 	
 	database.DB.Create(&execution)
 
-	payload := models.JobPayload{
-		JobID:       job.ID,
-		Name:        job.Name,
-		URL:         job.URL,
-		Method:      job.Method,
-		ExecutionID: executionID,
-		ScheduledAt: time.Now(),
-		MaxRetries:  DefaultMaxRetries,
-		RetryCount:  0,
-	}
+	payload := BuildPayload(job, executionID)
+	payload.Upstream = opts.Upstream
 
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal job payload: %w", err)
+		return "", fmt.Errorf("failed to marshal job payload: %w", err)
 	}
 
 	qs.client.Set(qs.ctx, pendingKey, executionID, 10*time.Minute)
@@ -79,12 +91,43 @@ func (qs *QueueService) PublishJob(job *models.Job) error {
 	if err != nil {
 		qs.client.Del(qs.ctx, pendingKey)
 		execution.Status = "failed"
+		execution.ErrorMessage = "failed to publish job to queue"
 		database.DB.Save(&execution)
-		return fmt.Errorf("failed to publish job to queue: %w", err)
+		return "", fmt.Errorf("failed to publish job to queue: %w", err)
 	}
 
 	log.Printf("Published job %s (execution %s) to queue", job.Name, executionID)
-	return nil
+	return executionID, nil
+}
+
+func BuildPayload(job *models.Job, executionID string) models.JobPayload {
+	return models.JobPayload{
+		JobID:                 job.ID,
+		UserID:                job.UserID,
+		Name:                  job.Name,
+		Type:                  job.Type,
+		Config:                job.Config,
+		URL:                   job.URL,
+		Method:                job.Method,
+		Headers:               job.Headers,
+		Body:                  job.Body,
+		ContentType:           job.ContentType,
+		Assertions:            job.Assertions,
+		TimeoutSeconds:        job.TimeoutSeconds,
+		FollowRedirects:       job.FollowRedirects,
+		MaxRedirects:          job.MaxRedirects,
+		MaxResponseBytes:      job.MaxResponseBytes,
+		SigningSecret:         job.SigningSecret,
+		Auth:                  job.Auth,
+		TLS:                   job.TLS,
+		CertExpiryWarningDays: job.CertExpiryWarningDays,
+		Completion:            job.Completion,
+		ExecutionID:           executionID,
+		ScheduledAt:           time.Now(),
+		Timezone:              job.Timezone,
+		MaxRetries:            DefaultMaxRetries,
+		RetryCount:            0,
+	}
 }
 
 
@@ -128,6 +171,9 @@ func (qs *QueueService) ConsumeJobs(workerID string, processFn func(*models.JobP
 
 
 func (qs *QueueService) handleJobResult(payload *models.JobPayload, result *models.JobResult) error {
+	if payload.AsyncPoll != nil {
+		return qs.handlePollResult(payload, result)
+	}
 
 	var execution models.JobExecution
 	if err := database.DB.First(&execution, "id = ?", result.ExecutionID).Error; err != nil {
@@ -136,18 +182,56 @@ func (qs *QueueService) handleJobResult(payload *models.JobPayload, result *mode
 
 	execution.Status = result.Status
 	execution.ResponseCode = result.ResponseCode
+	execution.AssertionResults = result.AssertionResults
+	execution.ErrorMessage = result.ErrorMessage
+	execution.LatencyMs = result.LatencyMs
+	execution.ResponseBody = result.ResponseBody
+	execution.ResponseHeaders = result.ResponseHeaders
+	execution.RemoteIP = result.RemoteIP
+	execution.Attempt = result.Attempt
+	execution.CertExpiresAt = result.CertExpiresAt
+	execution.CertIssuer = result.CertIssuer
+	execution.CertSANs = result.CertSANs
+	execution.StepResults = result.StepResults
 	execution.FinishedAt = &result.CompletedAt
 
+	if result.Status == "running" {
+		execution.FinishedAt = nil
+	}
+	retry := result.Status == "failed" && payload.RetryCount < payload.MaxRetries
+	if retry {
+		execution.Status = "retry"
+	}
+
+	// A completion callback can arrive before the worker reports back, so the
+	// result only applies while the execution is still marked as running.
+	saved, err := saveIfRunning(&execution)
+	if err != nil {
+		return err
+	}
+	if !saved {
+		log.Printf("Job %s (execution %s) already completed, ignoring worker result", payload.Name, execution.ID)
+		return nil
+	}
+
+	if result.Status == "running" {
+		qs.awaitCompletion(payload, result, &execution)
+		return nil
+	}
+
 	pendingKey := fmt.Sprintf("pending:%s", payload.JobID)
 
-	if result.Status == "failed" && payload.RetryCount < payload.MaxRetries {
+	if retry {
 		payload.RetryCount++
 		if err := qs.requeueForRetry(payload); err != nil {
 			log.Printf("Failed to requeue job for retry: %v", err)
 			qs.moveToDeadQueue(payload, result.ErrorMessage)
 			qs.client.Del(qs.ctx, pendingKey)
+			execution.Status = "failed"
+			if err := database.DB.Model(&execution).Update("status", execution.Status).Error; err != nil {
+				return err
+			}
 		} else {
-			execution.Status = "retry"
 			log.Printf("Job %s queued for retry (attempt %d/%d)", payload.Name, payload.RetryCount, payload.MaxRetries)
 		}
 	} else {
@@ -158,14 +242,90 @@ func (qs *QueueService) handleJobResult(payload *models.JobPayload, result *mode
 		}
 	}
 
-	return database.DB.Save(&execution).Error
+	if execution.Status != "retry" {
+		qs.triggerDownstream(payload, result)
+	}
+	return nil
+}
+
+// saveIfRunning writes the execution only if its stored status is still
+// "running" and reports whether the row was updated.
+func saveIfRunning(execution *models.JobExecution) (bool, error) {
+	res := database.DB.Model(execution).Where("status = ?", "running").Select("*").Updates(execution)
+	return res.RowsAffected > 0, res.Error
+}
+
+func (qs *QueueService) triggerDownstream(payload *models.JobPayload, result *models.JobResult) {
+	var dependencies []models.JobDependency
+	if err := database.DB.Where("upstream_job_id = ?", payload.JobID).Find(&dependencies).Error; err != nil {
+		log.Printf("Failed to load downstream jobs for %s: %v", payload.Name, err)
+		return
+	}
+
+	succeeded := result.Status != "failed"
+	rateLimiter := middleware.NewRateLimiter()
+
+	for _, dep := range dependencies {
+		switch dep.Condition {
+		case models.DependencyOnFailure:
+			if succeeded {
+				continue
+			}
+		case models.DependencyAlways:
+		default:
+			if !succeeded {
+				continue
+			}
+		}
+
+		var downstream models.Job
+		if err := database.DB.First(&downstream, "id = ?", dep.DownstreamJobID).Error; err != nil {
+			log.Printf("Failed to load downstream job %s: %v", dep.DownstreamJobID, err)
+			continue
+		}
+		if downstream.Status == models.JobStatusPaused {
+			log.Printf("Downstream job %s is paused, skipping", downstream.Name)
+			continue
+		}
+
+		allowed, _, _, err := rateLimiter.CheckRateLimit(downstream.UserID)
+		if err != nil {
+			log.Printf("Failed to check rate limit for job %s: %v", downstream.Name, err)
+		} else if !allowed {
+			log.Printf("Rate limit exceeded for user %s. Downstream job %s skipped", downstream.UserID, downstream.Name)
+			continue
+		}
+		if err := rateLimiter.RecordPing(downstream.UserID); err != nil {
+			log.Printf("Failed to record ping for user %s: %v", downstream.UserID, err)
+		}
+
+		upstream := &models.UpstreamContext{
+			JobID:        payload.JobID,
+			JobName:      payload.Name,
+			ExecutionID:  payload.ExecutionID,
+			Status:       result.Status,
+			ResponseCode: result.ResponseCode,
+		}
+		if dep.PassResponse {
+			upstream.ResponseBody = result.ResponseBody
+			upstream.Variables = result.Variables
+		}
+
+		log.Printf("Job %s finished with %s, triggering downstream job %s", payload.Name, result.Status, downstream.Name)
+		if _, err := qs.Publish(&downstream, PublishOptions{Trigger: models.TriggerDependency, Upstream: upstream}); err != nil && err != ErrJobPending {
+			log.Printf("Failed to publish downstream job %s: %v", downstream.Name, err)
+		}
+	}
 }
 
 
 func (qs *QueueService) requeueForRetry(payload *models.JobPayload) error {
 
 	delay := time.Duration(payload.RetryCount*payload.RetryCount) * time.Minute
-	
+	return qs.enqueueDelayed(payload, delay)
+}
+
+func (qs *QueueService) enqueueDelayed(payload *models.JobPayload, delay time.Duration) error {
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
 		return err
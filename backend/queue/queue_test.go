@@ -0,0 +1,278 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeStore is a tiny in-memory stand-in for postgres. SELECTs return every
+// row of the queried table and conditional UPDATEs on job_executions only
+// apply while the stored status is "running", mirroring the real WHERE clause.
+type fakeStore struct {
+	mu      sync.Mutex
+	tables  map[string][]map[string]driver.Value
+	queries []string
+}
+
+var (
+	tableName   = regexp.MustCompile(`(?:FROM|UPDATE) "(\w+)"`)
+	setColumn   = regexp.MustCompile(`"(\w+)"=\$(\d+)`)
+	fakeStoreMu sync.Mutex
+	activeStore *fakeStore
+)
+
+func init() {
+	sql.Register("cronnect-fake", fakeDriver{})
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{query}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct{ query string }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	store := activeStore
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.queries = append(store.queries, s.query)
+
+	match := tableName.FindStringSubmatch(s.query)
+	if !strings.HasPrefix(s.query, "UPDATE") || match == nil {
+		return driver.RowsAffected(0), nil
+	}
+	rows := store.tables[match[1]]
+	if len(rows) == 0 || (strings.Contains(s.query, "status = ") && rows[0]["status"] != "running") {
+		return driver.RowsAffected(0), nil
+	}
+	set := s.query[:strings.Index(s.query, " WHERE ")]
+	for _, column := range setColumn.FindAllStringSubmatch(set, -1) {
+		var index int
+		for _, c := range column[2] {
+			index = index*10 + int(c-'0')
+		}
+		rows[0][column[1]] = args[index-1]
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	store := activeStore
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.queries = append(store.queries, s.query)
+
+	match := tableName.FindStringSubmatch(s.query)
+	if match == nil {
+		return nil, errors.New("unsupported query: " + s.query)
+	}
+	rows := &fakeRows{}
+	for _, row := range store.tables[match[1]] {
+		if rows.columns == nil {
+			for column := range row {
+				rows.columns = append(rows.columns, column)
+			}
+		}
+		values := make([]driver.Value, len(rows.columns))
+		for i, column := range rows.columns {
+			values[i] = row[column]
+		}
+		rows.values = append(rows.values, values)
+	}
+	if rows.columns == nil {
+		rows.columns = []string{"id"}
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func (s *fakeStore) count(pattern string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, query := range s.queries {
+		if strings.Contains(query, pattern) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *fakeStore) executionStatus() driver.Value {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tables["job_executions"][0]["status"]
+}
+
+// recordingHook answers every redis command locally and remembers its name.
+type recordingHook struct {
+	mu       sync.Mutex
+	commands []string
+}
+
+func (h *recordingHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *recordingHook) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.mu.Lock()
+		h.commands = append(h.commands, cmd.Name())
+		h.mu.Unlock()
+		return nil
+	}
+}
+
+func (h *recordingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func (h *recordingHook) count(name string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, command := range h.commands {
+		if command == name {
+			n++
+		}
+	}
+	return n
+}
+
+func newTestQueue(t *testing.T, executionStatus string) (*QueueService, *fakeStore, *recordingHook) {
+	t.Helper()
+	fakeStoreMu.Lock()
+	t.Cleanup(fakeStoreMu.Unlock)
+
+	activeStore = &fakeStore{tables: map[string][]map[string]driver.Value{
+		"job_executions": {{"id": "exec-1", "job_id": "job-1", "status": executionStatus, "started_at": time.Now()}},
+		"jobs":           {{"id": "job-1", "name": "callback job"}},
+	}}
+
+	sqlDB, err := sql.Open("cronnect-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	database.DB = db
+
+	hook := &recordingHook{}
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	client.AddHook(hook)
+
+	return &QueueService{client: client, ctx: context.Background()}, activeStore, hook
+}
+
+func callbackPayload() *models.JobPayload {
+	return &models.JobPayload{
+		JobID:       "job-1",
+		ExecutionID: "exec-1",
+		Name:        "callback job",
+		Completion:  &models.JobCompletion{Mode: models.CompletionCallback},
+	}
+}
+
+func TestCallbackBeforeResponse(t *testing.T) {
+	qs, store, hook := newTestQueue(t, "running")
+
+	// The target calls back before the worker has reported its 202.
+	if err := qs.CompleteAsync("exec-1", "success", "", 200); err != nil {
+		t.Fatalf("CompleteAsync: %v", err)
+	}
+
+	err := qs.handleJobResult(callbackPayload(), &models.JobResult{
+		ExecutionID:  "exec-1",
+		Status:       "running",
+		ResponseCode: 202,
+		CompletedAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("handleJobResult: %v", err)
+	}
+
+	if status := store.executionStatus(); status != "success" {
+		t.Errorf("status = %v, want success", status)
+	}
+	if n := hook.count("zadd"); n != 0 {
+		t.Errorf("armed %d completion deadlines, want none", n)
+	}
+	if n := store.count(`FROM "job_dependencies"`); n != 1 {
+		t.Errorf("triggered downstream jobs %d times, want once", n)
+	}
+}
+
+func TestResponseBeforeCallback(t *testing.T) {
+	qs, store, hook := newTestQueue(t, "running")
+
+	err := qs.handleJobResult(callbackPayload(), &models.JobResult{
+		ExecutionID:  "exec-1",
+		Status:       "running",
+		ResponseCode: 202,
+		CompletedAt:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("handleJobResult: %v", err)
+	}
+	if n := hook.count("zadd"); n != 1 {
+		t.Errorf("armed %d completion deadlines, want 1", n)
+	}
+
+	if err := qs.CompleteAsync("exec-1", "success", "", 200); err != nil {
+		t.Fatalf("CompleteAsync: %v", err)
+	}
+	if err := qs.CompleteAsync("exec-1", "failed", "completion deadline exceeded", 0); err != ErrExecutionNotRunning {
+		t.Errorf("second completion err = %v, want ErrExecutionNotRunning", err)
+	}
+	if status := store.executionStatus(); status != "success" {
+		t.Errorf("status = %v, want success", status)
+	}
+	if n := store.count(`FROM "job_dependencies"`); n != 1 {
+		t.Errorf("triggered downstream jobs %d times, want once", n)
+	}
+}
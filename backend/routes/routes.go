@@ -37,6 +37,23 @@ func SetupRoutes(db *gorm.DB) *gin.Engine {
 	router.StaticFile("/", "/app/frontend/index.html")
 	
 	jobController := controllers.NewJobController(db)
+	secretController := controllers.NewSecretController(db)
+	settingsController := controllers.NewSettingsController(db)
+	calendarController := controllers.NewCalendarController(db)
+	callbackController := controllers.NewCallbackController(db)
+	heartbeatController := controllers.NewHeartbeatController(db)
+
+	router.POST("/callbacks/:token", callbackController.CompleteExecution)
+
+	pings := router.Group("/ping")
+	{
+		pings.GET("/:token", heartbeatController.PingSuccess)
+		pings.POST("/:token", heartbeatController.PingSuccess)
+		pings.GET("/:token/start", heartbeatController.PingStart)
+		pings.POST("/:token/start", heartbeatController.PingStart)
+		pings.GET("/:token/fail", heartbeatController.PingFail)
+		pings.POST("/:token/fail", heartbeatController.PingFail)
+	}
 	
 	protected := router.Group("/")
 	protected.Use(middleware.AuthRequired())
@@ -44,10 +61,31 @@ func SetupRoutes(db *gorm.DB) *gin.Engine {
 		protected.GET("/jobs", jobController.GetJobs)
 		protected.GET("/jobs/:id", jobController.GetJob)
 		protected.POST("/jobs", jobController.CreateJob)
+		protected.POST("/jobs/test", jobController.TestJob)
 		protected.PUT("/jobs/:id", jobController.UpdateJob)
 		protected.PATCH("/jobs/:id", jobController.UpdateJob)
 		protected.DELETE("/jobs/:id", jobController.DeleteJob)
+		protected.POST("/jobs/:id/run", jobController.RunJob)
+		protected.POST("/jobs/:id/pause", jobController.PauseJob)
+		protected.POST("/jobs/:id/resume", jobController.ResumeJob)
 		protected.GET("/rate-limit", jobController.GetRateLimit)
+		protected.GET("/certificates", jobController.GetCertificates)
+		protected.GET("/schedules/preview", jobController.PreviewSchedule)
+
+		protected.GET("/secrets", secretController.GetSecrets)
+		protected.POST("/secrets", secretController.CreateSecret)
+		protected.PUT("/secrets/:id", secretController.UpdateSecret)
+		protected.DELETE("/secrets/:id", secretController.DeleteSecret)
+
+		protected.GET("/calendars", calendarController.GetCalendars)
+		protected.GET("/calendars/:id", calendarController.GetCalendar)
+		protected.POST("/calendars", calendarController.CreateCalendar)
+		protected.PUT("/calendars/:id", calendarController.UpdateCalendar)
+		protected.DELETE("/calendars/:id", calendarController.DeleteCalendar)
+		protected.POST("/calendars/:id/import", calendarController.ImportCalendar)
+
+		protected.GET("/settings", settingsController.GetSettings)
+		protected.PUT("/settings", settingsController.UpdateSettings)
 	}
 
 	return router
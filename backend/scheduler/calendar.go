@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/google/uuid"
+)
+
+// calendarExclusion reports whether the job's calendar excludes the given
+// day, evaluated in the job's timezone.
+func calendarExclusion(job *models.Job, at time.Time) (*models.CalendarDate, bool) {
+	date := at.In(JobLocation(job)).Format(models.CalendarDateLayout)
+
+	var entry models.CalendarDate
+	if err := database.DB.Where("calendar_id = ? AND date = ?", job.CalendarID, date).First(&entry).Error; err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func recordCalendarSkip(job *models.Job, entry *models.CalendarDate, at time.Time) {
+	message := fmt.Sprintf("skipped: calendar excludes %s", entry.Date)
+	if entry.Name != "" {
+		message += fmt.Sprintf(" (%s)", entry.Name)
+	}
+	recordSkippedRun(job, message, at)
+}
+
+// recordSkippedRun stores a scheduled run that never reached the queue so the
+// execution history explains the gap.
+func recordSkippedRun(job *models.Job, message string, at time.Time) {
+	execution := models.JobExecution{
+		ID:           uuid.NewString(),
+		JobID:        job.ID,
+		StartedAt:    at,
+		FinishedAt:   &at,
+		Status:       "skipped",
+		Trigger:      models.TriggerSchedule,
+		ErrorMessage: message,
+	}
+	if err := database.DB.Create(&execution).Error; err != nil {
+		log.Printf("Failed to record skipped run for job %s: %v", job.Name, err)
+	}
+	log.Printf("Job %s %s", job.Name, message)
+}
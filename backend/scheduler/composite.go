@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/robfig/cron/v3"
+)
+
+const maxExcludedSkips = 10000
+
+// exclusion reports whether t is excluded and, if so, the instant to resume
+// searching from so long windows are skipped in one step.
+type exclusion func(t time.Time) (bool, time.Time)
+
+// compositeSchedule fires at the earliest time of any included schedule that
+// no exclusion matches. Coinciding fire times collapse into a single run.
+type compositeSchedule struct {
+	includes []cron.Schedule
+	excludes []exclusion
+}
+
+func (s compositeSchedule) Next(t time.Time) time.Time {
+	for i := 0; i < maxExcludedSkips; i++ {
+		var next time.Time
+		for _, schedule := range s.includes {
+			candidate := schedule.Next(t)
+			if !candidate.IsZero() && (next.IsZero() || candidate.Before(next)) {
+				next = candidate
+			}
+		}
+		if next.IsZero() {
+			return next
+		}
+
+		resume, excluded := next, false
+		for _, exclude := range s.excludes {
+			if matched, until := exclude(next); matched {
+				excluded = true
+				if until.After(resume) {
+					resume = until
+				}
+			}
+		}
+		if !excluded {
+			return next
+		}
+		t = resume
+	}
+	return time.Time{}
+}
+
+func buildExclusion(rule models.ScheduleExclusion, loc *time.Location, hashKey string) (exclusion, error) {
+	switch {
+	case rule.Cron != "":
+		schedule, err := ParseSchedule(rule.Cron, loc, hashKey)
+		if err != nil {
+			return nil, err
+		}
+		granularity := time.Minute
+		if len(strings.Fields(stripTimezone(rule.Cron))) == 6 {
+			granularity = time.Second
+		}
+		return func(t time.Time) (bool, time.Time) {
+			slot := t.Truncate(granularity)
+			return schedule.Next(slot.Add(-time.Nanosecond)).Equal(slot), t
+		}, nil
+
+	case rule.From != nil || rule.To != nil:
+		if rule.From == nil || rule.To == nil || !rule.To.After(*rule.From) {
+			return nil, errors.New("from and to must both be set with to after from")
+		}
+		from, to := *rule.From, *rule.To
+		return func(t time.Time) (bool, time.Time) {
+			if t.Before(from) || !t.Before(to) {
+				return false, t
+			}
+			return true, to.Add(-time.Nanosecond)
+		}, nil
+
+	case rule.StartTime != "" || rule.EndTime != "":
+		start, err := parseClock(rule.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClock(rule.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		if start == end {
+			return nil, errors.New("start_time and end_time must differ")
+		}
+		// Compare wall-clock readings rather than time elapsed since midnight so
+		// the window stays put on days with a DST transition.
+		return func(t time.Time) (bool, time.Time) {
+			local := t.In(loc)
+			clock := local.Hour()*60 + local.Minute()
+			resume := func(days int) time.Time {
+				return time.Date(local.Year(), local.Month(), local.Day()+days, end/60, end%60, 0, 0, loc).Add(-time.Nanosecond)
+			}
+			switch {
+			case start < end && clock >= start && clock < end:
+				return true, resume(0)
+			case start > end && clock >= start:
+				return true, resume(1)
+			case start > end && clock < end:
+				return true, resume(0)
+			}
+			return false, t
+		}, nil
+	}
+
+	return nil, errors.New("an exclusion needs cron, from/to or start_time/end_time")
+}
+
+// parseClock returns an HH:MM time of day as minutes past midnight.
+func parseClock(value string) (int, error) {
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, errors.New("times must use the HH:MM format")
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+)
+
+func ptrTime(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		panic(err)
+	}
+	return &t
+}
+
+func TestCompositeSchedule(t *testing.T) {
+	tests := []struct {
+		name string
+		job  models.Job
+		from string
+		want []string
+	}{
+		{
+			name: "coinciding fire times run once",
+			job:  models.Job{Schedule: "0 9 * * *", Schedules: []string{"0 9 * * 1-5", "0 */12 * * *"}},
+			from: "2026-10-19T00:00:00Z",
+			want: []string{"2026-10-19T09:00:00Z", "2026-10-19T12:00:00Z", "2026-10-20T00:00:00Z", "2026-10-20T09:00:00Z"},
+		},
+		{
+			name: "schedules list without a primary schedule",
+			job:  models.Job{Schedules: []string{"0 8 * * *", "0 20 * * *"}},
+			from: "2026-10-19T10:00:00Z",
+			want: []string{"2026-10-19T20:00:00Z", "2026-10-20T08:00:00Z"},
+		},
+		{
+			name: "cron exclusion skips matching days",
+			job: models.Job{
+				Schedule:   "0 9 * * *",
+				Exclusions: []models.ScheduleExclusion{{Cron: "* * * * 0,6"}},
+			},
+			from: "2026-10-23T00:00:00Z",
+			want: []string{"2026-10-23T09:00:00Z", "2026-10-26T09:00:00Z"},
+		},
+		{
+			name: "absolute range exclusion",
+			job: models.Job{
+				Schedule:   "0 9 * * *",
+				Exclusions: []models.ScheduleExclusion{{From: ptrTime("2026-12-24T00:00:00Z"), To: ptrTime("2026-12-27T00:00:00Z")}},
+			},
+			from: "2026-12-23T00:00:00Z",
+			want: []string{"2026-12-23T09:00:00Z", "2026-12-27T09:00:00Z"},
+		},
+		{
+			name: "daily window exclusion",
+			job: models.Job{
+				Schedule:   "0 * * * *",
+				Exclusions: []models.ScheduleExclusion{{StartTime: "12:00", EndTime: "14:00"}},
+			},
+			from: "2026-10-19T10:30:00Z",
+			want: []string{"2026-10-19T11:00:00Z", "2026-10-19T14:00:00Z", "2026-10-19T15:00:00Z"},
+		},
+		{
+			name: "overnight window exclusion",
+			job: models.Job{
+				Schedule:   "0 */4 * * *",
+				Exclusions: []models.ScheduleExclusion{{StartTime: "22:00", EndTime: "06:00"}},
+			},
+			from: "2026-10-19T15:00:00Z",
+			want: []string{"2026-10-19T16:00:00Z", "2026-10-19T20:00:00Z", "2026-10-20T08:00:00Z"},
+		},
+		{
+			name: "window uses the job timezone",
+			job: models.Job{
+				Schedule:   "0 * * * *",
+				Timezone:   "America/New_York",
+				Exclusions: []models.ScheduleExclusion{{StartTime: "09:00", EndTime: "17:00"}},
+			},
+			from: "2026-10-19T12:30:00Z",
+			want: []string{"2026-10-19T21:00:00Z", "2026-10-19T22:00:00Z"},
+		},
+		{
+			name: "window keeps its wall-clock hours on a DST day",
+			job: models.Job{
+				Schedule:   "0 * * * *",
+				Timezone:   "America/New_York",
+				Exclusions: []models.ScheduleExclusion{{StartTime: "09:00", EndTime: "17:00"}},
+			},
+			from: "2026-03-08T11:30:00Z",
+			want: []string{"2026-03-08T12:00:00Z", "2026-03-08T21:00:00Z", "2026-03-08T22:00:00Z"},
+		},
+		{
+			name: "exclusions combine with a start and end window",
+			job: models.Job{
+				Schedules:  []string{"0 9 * * *", "0 18 * * *"},
+				Exclusions: []models.ScheduleExclusion{{Cron: "0 18 * * 5"}},
+				StartsAt:   ptrTime("2026-10-22T12:00:00Z"),
+				EndsAt:     ptrTime("2026-10-24T12:00:00Z"),
+			},
+			from: "2026-10-19T00:00:00Z",
+			want: []string{"2026-10-22T18:00:00Z", "2026-10-23T09:00:00Z", "2026-10-24T09:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := JobSchedule(&tt.job)
+			if err != nil {
+				t.Fatal(err)
+			}
+			from, _ := time.Parse(time.RFC3339, tt.from)
+			// Ask for one extra run when the job has an end so the test also
+			// checks that the schedule stops.
+			count := len(tt.want)
+			if tt.job.EndsAt != nil {
+				count++
+			}
+			got := formatRuns(NextRuns(schedule, from, count))
+			if len(got) != len(tt.want) {
+				t.Fatalf("runs = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("runs = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCompositeScheduleFullyExcluded(t *testing.T) {
+	job := models.Job{Schedule: "0 9 * * *", Exclusions: []models.ScheduleExclusion{{Cron: "* * * * *"}}}
+	schedule, err := JobSchedule(&job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Next = %s, want zero for a schedule that is always excluded", next)
+	}
+}
+
+func TestBuildExclusionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		rule models.ScheduleExclusion
+	}{
+		{"empty rule", models.ScheduleExclusion{}},
+		{"invalid cron", models.ScheduleExclusion{Cron: "every day"}},
+		{"from without to", models.ScheduleExclusion{From: ptrTime("2026-01-01T00:00:00Z")}},
+		{"to before from", models.ScheduleExclusion{From: ptrTime("2026-01-02T00:00:00Z"), To: ptrTime("2026-01-01T00:00:00Z")}},
+		{"start_time without end_time", models.ScheduleExclusion{StartTime: "09:00"}},
+		{"invalid clock", models.ScheduleExclusion{StartTime: "9am", EndTime: "17:00"}},
+		{"empty window", models.ScheduleExclusion{StartTime: "09:00", EndTime: "09:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildExclusion(tt.rule, time.UTC, ""); err == nil {
+				t.Error("buildExclusion succeeded, want error")
+			}
+		})
+	}
+
+	job := models.Job{Schedule: "0 9 * * *", Schedules: []string{"not a cron"}}
+	if _, err := JobSchedule(&job); err == nil {
+		t.Error("JobSchedule accepted an invalid extra schedule")
+	}
+}
+
+func TestDescribeJob(t *testing.T) {
+	job := models.Job{
+		Schedule:  "0 9 * * 1-5",
+		Schedules: []string{"30 12 * * *"},
+		Exclusions: []models.ScheduleExclusion{
+			{Cron: "* * * * 3"},
+			{StartTime: "12:00", EndTime: "13:00"},
+			{From: ptrTime("2026-12-24T00:00:00Z"), To: ptrTime("2026-12-27T00:00:00Z")},
+		},
+	}
+	want := "At 09:00, Monday through Friday; at 12:30, except every minute, only on Wednesday, " +
+		"except between 12:00 and 13:00, except from 2026-12-24 00:00 to 2026-12-27 00:00"
+	got, err := DescribeJob(&job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("DescribeJob = %q, want %q", got, want)
+	}
+}
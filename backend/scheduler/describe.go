@@ -0,0 +1,423 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	monthNames   = []string{"", "January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	weekdayNames = []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+	monthAbbrevs   = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	weekdayAbbrevs = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+	descriptorSpecs = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+type fieldItem struct {
+	any       bool
+	low, high int
+	step      int
+}
+
+func (i fieldItem) single() bool {
+	return !i.any && i.low == i.high && i.step == 0
+}
+
+type cronField []fieldItem
+
+func (f cronField) any() bool {
+	return len(f) == 1 && f[0].any && f[0].step == 0
+}
+
+// star reports whether cron treats the field as unrestricted: it contains a
+// bare "*" or "?" (or "*/1"). Day of month and day of week are combined with
+// AND when either is a star, and with OR otherwise.
+func (f cronField) star() bool {
+	for _, item := range f {
+		if item.any && item.step <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (f cronField) singles() bool {
+	for _, item := range f {
+		if !item.single() {
+			return false
+		}
+	}
+	return true
+}
+
+// NextRuns returns the next n fire times of schedule after from.
+func NextRuns(schedule cron.Schedule, from time.Time, n int) []time.Time {
+	runs := make([]time.Time, 0, n)
+	next := from
+	for len(runs) < n {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+		runs = append(runs, next)
+	}
+	return runs
+}
+
+// Describe renders a cron expression as an English sentence such as
+// "At 09:00, Monday through Friday".
+func Describe(expr string) (string, error) {
+	expr = stripTimezone(expr)
+
+	if strings.HasPrefix(expr, "@every ") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, "@every ")))
+		if err != nil {
+			return "", fmt.Errorf("invalid @every interval: %w", err)
+		}
+		return "Every " + describeDuration(interval), nil
+	}
+	if spec, ok := descriptorSpecs[expr]; ok {
+		expr = spec
+	}
+
+	parts := strings.Fields(expr)
+	if len(parts) != 5 && len(parts) != 6 {
+		return "", errors.New("expected 5 or 6 cron fields")
+	}
+
+	seconds := cronField{{low: 0, high: 0}}
+	if len(parts) == 6 {
+		var err error
+		if seconds, err = parseField(parts[0], nil, 0, 59); err != nil {
+			return "", fmt.Errorf("second: %w", err)
+		}
+		parts = parts[1:]
+	}
+
+	minutes, err := parseField(parts[0], nil, 0, 59)
+	if err != nil {
+		return "", fmt.Errorf("minute: %w", err)
+	}
+	hours, err := parseField(parts[1], nil, 0, 23)
+	if err != nil {
+		return "", fmt.Errorf("hour: %w", err)
+	}
+	days, err := parseField(parts[2], nil, 1, 31)
+	if err != nil {
+		return "", fmt.Errorf("day of month: %w", err)
+	}
+	months, err := parseField(parts[3], monthAbbrevs, 1, 12)
+	if err != nil {
+		return "", fmt.Errorf("month: %w", err)
+	}
+	weekdays, err := parseField(parts[4], weekdayAbbrevs, 0, 7)
+	if err != nil {
+		return "", fmt.Errorf("day of week: %w", err)
+	}
+
+	var b strings.Builder
+	b.WriteString(describeTime(seconds, minutes, hours))
+	if days.star() || weekdays.star() {
+		b.WriteString(describeDays(days))
+		b.WriteString(describeWeekdays(weekdays))
+	} else {
+		b.WriteString(describeDayOrWeekday(days, weekdays))
+	}
+	b.WriteString(describeMonths(months))
+	return b.String(), nil
+}
+
+// DescribeJob describes the combined schedule of a job: every inclusion
+// expression followed by its exclusions.
+func DescribeJob(job *models.Job) (string, error) {
+	var parts []string
+	for _, expr := range job.Expressions() {
+		expanded, err := ExpandHashes(expr, job.ID)
+		if err != nil {
+			return "", err
+		}
+		description, err := Describe(expanded)
+		if err != nil {
+			return "", err
+		}
+		if len(parts) > 0 {
+			description = strings.ToLower(description[:1]) + description[1:]
+		}
+		parts = append(parts, description)
+	}
+	description := strings.Join(parts, "; ")
+
+	for _, rule := range job.Exclusions {
+		switch {
+		case rule.Cron != "":
+			expanded, err := ExpandHashes(rule.Cron, job.ID)
+			if err != nil {
+				return "", err
+			}
+			excluded, err := Describe(expanded)
+			if err != nil {
+				return "", err
+			}
+			description += ", except " + strings.ToLower(excluded[:1]) + excluded[1:]
+		case rule.From != nil && rule.To != nil:
+			loc := JobLocation(job)
+			description += fmt.Sprintf(", except from %s to %s",
+				rule.From.In(loc).Format("2006-01-02 15:04"), rule.To.In(loc).Format("2006-01-02 15:04"))
+		case rule.StartTime != "":
+			description += fmt.Sprintf(", except between %s and %s", rule.StartTime, rule.EndTime)
+		}
+	}
+	return description, nil
+}
+
+func parseField(text string, names map[string]int, min, max int) (cronField, error) {
+	var field cronField
+	for _, part := range strings.Split(text, ",") {
+		item := fieldItem{}
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+		if hasStep {
+			step, err := strconv.Atoi(stepText)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepText)
+			}
+			item.step = step
+		}
+
+		switch {
+		case rangeText == "*" || rangeText == "?":
+			item.any = true
+			item.low, item.high = min, max
+		default:
+			lowText, highText, isRange := strings.Cut(rangeText, "-")
+			low, err := fieldValue(lowText, names, min, max)
+			if err != nil {
+				return nil, err
+			}
+			high := low
+			if isRange {
+				if high, err = fieldValue(highText, names, min, max); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				high = max
+			}
+			item.low, item.high = low, high
+		}
+		field = append(field, item)
+	}
+	return field, nil
+}
+
+func fieldValue(text string, names map[string]int, min, max int) (int, error) {
+	if value, ok := names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < min || value > max {
+		return 0, fmt.Errorf("value %q out of range", text)
+	}
+	return value, nil
+}
+
+func describeTime(seconds, minutes, hours cronField) string {
+	if len(seconds) == 1 && seconds[0].single() && seconds[0].low == 0 {
+		return describeMinutesAndHours(minutes, hours)
+	}
+
+	var phrase string
+	switch {
+	case seconds.any():
+		phrase = "Every second"
+	case len(seconds) == 1 && seconds[0].any:
+		phrase = fmt.Sprintf("Every %d seconds", seconds[0].step)
+	case seconds.singles():
+		phrase = "At second " + joinAnd(listItems(seconds, strconv.Itoa, "seconds"))
+	default:
+		phrase = "At seconds " + joinAnd(listItems(seconds, strconv.Itoa, "seconds"))
+	}
+
+	if minutes.any() && hours.any() {
+		return phrase
+	}
+	rest := describeMinutesAndHours(minutes, hours)
+	return phrase + ", " + strings.ToLower(rest[:1]) + rest[1:]
+}
+
+func describeMinutesAndHours(minutes, hours cronField) string {
+	if len(minutes) == 1 && minutes[0].single() && hours.singles() {
+		times := make([]string, 0, len(hours))
+		for _, hour := range hours {
+			times = append(times, clock(hour.low, minutes[0].low))
+		}
+		return "At " + joinAnd(times)
+	}
+
+	onTheHour := len(minutes) == 1 && minutes[0].single() && minutes[0].low == 0
+	if onTheHour && len(hours) == 1 && hours[0].any {
+		if hours[0].step <= 1 {
+			return "Every hour"
+		}
+		return fmt.Sprintf("Every %d hours", hours[0].step)
+	}
+
+	var phrase string
+	switch {
+	case minutes.any():
+		phrase = "Every minute"
+	case len(minutes) == 1 && minutes[0].any:
+		phrase = fmt.Sprintf("Every %d minutes", minutes[0].step)
+	case onTheHour:
+		phrase = "Every hour"
+	case len(minutes) == 1 && minutes[0].single() && minutes[0].low == 1:
+		phrase = "At 1 minute past the hour"
+	case minutes.singles():
+		phrase = "At " + joinAnd(listItems(minutes, strconv.Itoa, "minutes")) + " minutes past the hour"
+	case minutes[0].step > 0:
+		phrase = "Every" + strings.TrimPrefix(joinAnd(listItems(minutes, strconv.Itoa, "minutes")), "every") + " past the hour"
+	default:
+		phrase = "At minutes " + joinAnd(listItems(minutes, strconv.Itoa, "minutes")) + " past the hour"
+	}
+
+	hourName := func(h int) string { return clock(h, 0) }
+	switch {
+	case hours.any():
+	case len(hours) == 1 && hours[0].any:
+		phrase += fmt.Sprintf(", every %d hours", hours[0].step)
+	case len(hours) == 1 && hours[0].step == 0:
+		phrase += ", between " + clock(hours[0].low, 0) + " and " + clock(hours[0].high, 59)
+	default:
+		phrase += ", during the " + joinAnd(listItems(hours, hourName, "hours")) + " hours"
+	}
+	return phrase
+}
+
+func describeDays(days cronField) string {
+	switch {
+	case days.any():
+		return ""
+	case len(days) == 1 && days[0].any:
+		return fmt.Sprintf(", every %d days", days[0].step)
+	case days.singles() && len(days) == 1:
+		return fmt.Sprintf(", on day %d of the month", days[0].low)
+	default:
+		return ", on days " + joinAnd(listItems(days, strconv.Itoa, "days")) + " of the month"
+	}
+}
+
+// describeDayOrWeekday covers a schedule restricting both day fields, which
+// fires when either one matches.
+func describeDayOrWeekday(days, weekdays cronField) string {
+	dayText := strings.TrimPrefix(describeDays(days), ", ")
+	weekdayText := strings.TrimPrefix(describeWeekdays(weekdays), ", ")
+	weekdayText = strings.TrimPrefix(weekdayText, "only ")
+	if !strings.HasPrefix(weekdayText, "on ") {
+		weekdayText = "on " + weekdayText
+	}
+	return ", " + dayText + " or " + weekdayText
+}
+
+func describeWeekdays(weekdays cronField) string {
+	name := func(d int) string { return weekdayNames[d] }
+	switch {
+	case weekdays.any():
+		return ""
+	case len(weekdays) == 1 && !weekdays[0].any && weekdays[0].step == 0 && weekdays[0].low != weekdays[0].high:
+		return ", " + name(weekdays[0].low) + " through " + name(weekdays[0].high)
+	default:
+		return ", only on " + joinAnd(listItems(weekdays, name, "days of the week"))
+	}
+}
+
+func describeMonths(months cronField) string {
+	name := func(m int) string { return monthNames[m] }
+	switch {
+	case months.any():
+		return ""
+	case len(months) == 1 && months[0].any:
+		return fmt.Sprintf(", every %d months", months[0].step)
+	case len(months) == 1 && months[0].step == 0 && months[0].low != months[0].high:
+		return ", " + name(months[0].low) + " through " + name(months[0].high)
+	default:
+		return ", only in " + joinAnd(listItems(months, name, "months"))
+	}
+}
+
+func listItems(field cronField, name func(int) string, unit string) []string {
+	items := make([]string, 0, len(field))
+	for _, item := range field {
+		switch {
+		case item.single():
+			items = append(items, name(item.low))
+		case item.step == 0:
+			items = append(items, name(item.low)+" through "+name(item.high))
+		case item.any:
+			items = append(items, fmt.Sprintf("every %d %s", item.step, unit))
+		default:
+			items = append(items, fmt.Sprintf("every %d %s from %s through %s", item.step, unit, name(item.low), name(item.high)))
+		}
+	}
+	return items
+}
+
+func describeDuration(d time.Duration) string {
+	var parts []string
+	units := []struct {
+		size time.Duration
+		name string
+	}{{time.Hour, "hour"}, {time.Minute, "minute"}, {time.Second, "second"}}
+	for _, unit := range units {
+		n := int(d / unit.size)
+		d -= time.Duration(n) * unit.size
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+unit.name)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, unit.name))
+		}
+	}
+	if len(parts) == 0 {
+		return d.String()
+	}
+	return joinAnd(parts)
+}
+
+func stripTimezone(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		if i := strings.IndexByte(expr, ' '); i >= 0 {
+			return strings.TrimSpace(expr[i:])
+		}
+	}
+	return expr
+}
+
+func clock(hour, minute int) string {
+	return fmt.Sprintf("%02d:%02d", hour, minute)
+}
+
+func joinAnd(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+	}
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import "testing"
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"* * * * *", "Every minute"},
+		{"*/15 * * * *", "Every 15 minutes"},
+		{"0 * * * *", "Every hour"},
+		{"0 */2 * * *", "Every 2 hours"},
+		{"15 */2 * * *", "At 15 minutes past the hour, every 2 hours"},
+		{"1 * * * *", "At 1 minute past the hour"},
+		{"5,35 * * * *", "At 5 and 35 minutes past the hour"},
+		{"0 9-17 * * *", "Every hour, between 09:00 and 17:59"},
+		{"*/10 9-17 * * 1-5", "Every 10 minutes, between 09:00 and 17:59, Monday through Friday"},
+		{"0 0 * * *", "At 00:00"},
+		{"0 9,17 * * *", "At 09:00 and 17:00"},
+		{"0 9 * * 1-5", "At 09:00, Monday through Friday"},
+		{"0 0 * * 0,6", "At 00:00, only on Sunday and Saturday"},
+		{"0 0 1 * *", "At 00:00, on day 1 of the month"},
+		{"0 0 1,15 * *", "At 00:00, on days 1 and 15 of the month"},
+		{"30 4 1 jan *", "At 04:30, on day 1 of the month, only in January"},
+		{"0 0 ? * 1", "At 00:00, only on Monday"},
+		{"0 0 1 * 1", "At 00:00, on day 1 of the month or on Monday"},
+		{"0 0 1 * 1-5", "At 00:00, on day 1 of the month or on Monday through Friday"},
+		{"0 0 */2 * 1", "At 00:00, every 2 days or on Monday"},
+		{"*/5 * * * * *", "Every 5 seconds"},
+		{"30 15 10 * * *", "At second 30, at 10:15"},
+		{"@daily", "At 00:00"},
+		{"@every 90m", "Every 1 hour and 30 minutes"},
+		{"CRON_TZ=Europe/London 0 9 * * *", "At 09:00"},
+	}
+	for _, tt := range tests {
+		got, err := Describe(tt.expr)
+		if err != nil {
+			t.Errorf("Describe(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "0 0 L * *", "61 * * * *", "@every soon"} {
+		if got, err := Describe(expr); err == nil {
+			t.Errorf("Describe(%q) = %q, want error", expr, got)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/google/uuid"
+)
+
+var heartbeatRank = map[string]int{
+	models.HeartbeatLate: 1,
+	models.HeartbeatDown: 2,
+}
+
+func checkHeartbeats() {
+	var jobs []models.Job
+	if err := database.DB.Where("type = ? AND status = ? AND last_ping_at IS NOT NULL", models.JobTypeHeartbeat, models.JobStatusActive).Find(&jobs).Error; err != nil {
+		log.Printf("Failed to load heartbeat jobs: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, job := range jobs {
+		checkHeartbeat(&job, now)
+	}
+}
+
+// checkHeartbeat escalates a monitor to late once the expected ping time has
+// passed and to down once the grace period has run out. Only a ping resets it.
+func checkHeartbeat(job *models.Job, now time.Time) {
+	schedule, err := JobSchedule(job)
+	if err != nil {
+		log.Printf("Invalid schedule for heartbeat %s: %v", job.Name, err)
+		return
+	}
+	config, err := models.ParseHeartbeatConfig(job.Config)
+	if err != nil {
+		log.Printf("Invalid config for heartbeat %s: %v", job.Name, err)
+		return
+	}
+
+	expected := schedule.Next(*job.LastPingAt)
+	if expected.IsZero() {
+		return
+	}
+	grace := time.Duration(config.GraceSeconds) * time.Second
+
+	state := models.HeartbeatUp
+	if now.After(expected.Add(grace)) {
+		state = models.HeartbeatDown
+	} else if now.After(expected) {
+		state = models.HeartbeatLate
+	}
+	if heartbeatRank[state] <= heartbeatRank[job.HeartbeatState] {
+		return
+	}
+
+	update := database.DB.Model(&models.Job{}).
+		Where("id = ? AND heartbeat_state = ?", job.ID, job.HeartbeatState).
+		Update("heartbeat_state", state)
+	if update.Error != nil {
+		log.Printf("Failed to update heartbeat %s: %v", job.Name, update.Error)
+		return
+	}
+	if update.RowsAffected == 0 {
+		return
+	}
+
+	execution := models.JobExecution{
+		ID:           uuid.NewString(),
+		JobID:        job.ID,
+		StartedAt:    expected,
+		FinishedAt:   &now,
+		Status:       state,
+		Trigger:      models.TriggerHeartbeat,
+		ErrorMessage: fmt.Sprintf("No ping received since %s (expected by %s)", job.LastPingAt.Format(time.RFC3339), expected.Format(time.RFC3339)),
+	}
+	if err := database.DB.Create(&execution).Error; err != nil {
+		log.Printf("Failed to record heartbeat execution for %s: %v", job.Name, err)
+	}
+	log.Printf("Heartbeat %s is %s", job.Name, state)
+}
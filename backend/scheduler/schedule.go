@@ -0,0 +1,331 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/robfig/cron/v3"
+)
+
+var parser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type fieldBounds struct {
+	min, max int
+}
+
+// Hashed values stay within 1-28 for day of month so they exist every month.
+var (
+	fiveFieldBounds = []fieldBounds{{0, 59}, {0, 23}, {1, 28}, {1, 12}, {0, 6}}
+	sixFieldBounds  = append([]fieldBounds{{0, 59}}, fiveFieldBounds...)
+)
+
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+func JobLocation(job *models.Job) *time.Location {
+	loc, err := LoadLocation(job.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+type onceSchedule struct {
+	at time.Time
+}
+
+func (s onceSchedule) Next(t time.Time) time.Time {
+	if t.Before(s.at) {
+		return s.at
+	}
+	return time.Time{}
+}
+
+// windowedSchedule limits a schedule to fire times between start and end.
+// A zero time from Next tells cron the entry will not run again.
+type windowedSchedule struct {
+	schedule   cron.Schedule
+	start, end *time.Time
+}
+
+func (s windowedSchedule) Next(t time.Time) time.Time {
+	if s.start != nil && t.Before(*s.start) {
+		t = s.start.Add(-time.Second)
+	}
+	next := s.schedule.Next(t)
+	if s.end != nil && next.After(*s.end) {
+		return time.Time{}
+	}
+	return next
+}
+
+func JobSchedule(job *models.Job) (cron.Schedule, error) {
+	if job.RunAt != nil {
+		return onceSchedule{at: *job.RunAt}, nil
+	}
+
+	loc := JobLocation(job)
+	expressions := job.Expressions()
+	if len(expressions) == 0 {
+		return nil, errors.New("job has no schedule")
+	}
+
+	var schedule cron.Schedule
+	if len(expressions) == 1 && len(job.Exclusions) == 0 {
+		parsed, err := ParseSchedule(expressions[0], loc, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		schedule = parsed
+	} else {
+		composite := compositeSchedule{}
+		for _, expr := range expressions {
+			parsed, err := ParseSchedule(expr, loc, job.ID)
+			if err != nil {
+				return nil, fmt.Errorf("schedule %q: %w", expr, err)
+			}
+			composite.includes = append(composite.includes, parsed)
+		}
+		for i, exclusion := range job.Exclusions {
+			matcher, err := buildExclusion(exclusion, loc, job.ID)
+			if err != nil {
+				return nil, fmt.Errorf("exclusion %d: %w", i+1, err)
+			}
+			composite.excludes = append(composite.excludes, matcher)
+		}
+		schedule = composite
+	}
+
+	if job.StartsAt == nil && job.EndsAt == nil {
+		return schedule, nil
+	}
+	return windowedSchedule{schedule: schedule, start: job.StartsAt, end: job.EndsAt}, nil
+}
+
+// ParseSchedule parses a cron expression (5 or 6 fields, or a descriptor)
+// evaluated in loc. "H" fields are hashed from hashKey. An explicit CRON_TZ=
+// prefix in the expression takes precedence over loc.
+func ParseSchedule(expr string, loc *time.Location, hashKey string) (cron.Schedule, error) {
+	expanded, err := ExpandHashes(expr, hashKey)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(expanded, "TZ=") || strings.HasPrefix(expanded, "CRON_TZ=") {
+		name, rest, ok := strings.Cut(expanded[strings.IndexByte(expanded, '=')+1:], " ")
+		if !ok {
+			return nil, errors.New("missing schedule after timezone")
+		}
+		if loc, err = LoadLocation(name); err != nil {
+			return nil, err
+		}
+		expanded = strings.TrimSpace(rest)
+	}
+
+	schedule, err := parser.Parse(expanded)
+	if err != nil {
+		return nil, err
+	}
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return schedule, nil
+	}
+	spec.Location = time.UTC
+	return wallClockSchedule{spec: spec, loc: loc, fixed: fixedTimeOfDay(expanded)}, nil
+}
+
+// fixedTimeOfDay reports whether the time fields of a parsed expression name
+// specific values. As in Vixie cron, only these jobs get DST adjustments;
+// wildcard and stepped schedules run on real instants.
+func fixedTimeOfDay(expr string) bool {
+	fields := strings.Fields(expr)
+	if len(fields) == 1 {
+		return fields[0] != "@hourly"
+	}
+	for _, field := range fields[:len(fields)-3] {
+		if strings.ContainsAny(field, "*/") {
+			return false
+		}
+	}
+	return true
+}
+
+// maxWallClockSteps bounds the search through a repeated hour, where every
+// wall time resolves to an instant that has already passed.
+const maxWallClockSteps = 3700
+
+// wallClockSchedule evaluates a cron spec against local wall-clock time in
+// loc. For fixed times of day, times skipped by a DST gap run at the shifted
+// instant (02:30 becomes 03:30) and times repeated when clocks go back fire
+// only on their first occurrence. Other schedules fire at every instant whose
+// local reading matches, so a repeated hour runs twice and a skipped one not
+// at all.
+type wallClockSchedule struct {
+	spec  *cron.SpecSchedule
+	loc   *time.Location
+	fixed bool
+}
+
+func (s wallClockSchedule) Next(t time.Time) time.Time {
+	if !s.fixed {
+		return s.nextInstant(t)
+	}
+	cursor := wallTime(t.In(s.loc))
+	for i := 0; i < maxWallClockSteps; i++ {
+		next := s.spec.Next(cursor)
+		if next.IsZero() {
+			return next
+		}
+		if instant := s.instant(next); instant.After(t) {
+			return instant
+		}
+		cursor = next
+	}
+	return time.Time{}
+}
+
+// nextInstant searches one UTC offset period at a time, within which local
+// readings increase with the instant they label.
+func (s wallClockSchedule) nextInstant(t time.Time) time.Time {
+	local := t.In(s.loc)
+	cursor := wallTime(local)
+	for i := 0; i < maxWallClockSteps; i++ {
+		_, offset := local.Zone()
+		_, end := local.ZoneBounds()
+		next := s.spec.Next(cursor)
+		if next.IsZero() {
+			return next
+		}
+		instant := next.Add(-time.Duration(offset) * time.Second).In(s.loc)
+		if end.IsZero() || instant.Before(end) {
+			return instant
+		}
+		local = end.In(s.loc)
+		cursor = wallTime(local).Add(-time.Nanosecond)
+	}
+	return time.Time{}
+}
+
+// instant resolves a wall time to the earliest matching instant in loc, or
+// for a wall time inside a DST gap, to the instant the gap shifts it to.
+func (s wallClockSchedule) instant(wall time.Time) time.Time {
+	approx := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), s.loc)
+	_, offsetBefore := approx.Add(-12 * time.Hour).Zone()
+	_, offsetAfter := approx.Add(12 * time.Hour).Zone()
+
+	early := wall.Add(-time.Duration(offsetBefore) * time.Second).In(s.loc)
+	late := wall.Add(-time.Duration(offsetAfter) * time.Second).In(s.loc)
+	if late.Before(early) {
+		early, late = late, early
+	}
+	switch {
+	case wallTime(early).Equal(wall):
+		return early
+	case wallTime(late).Equal(wall):
+		return late
+	}
+	return wall.Add(-time.Duration(offsetBefore) * time.Second).In(s.loc)
+}
+
+// wallTime re-labels the clock reading of t as UTC so cron fields can be
+// matched without the zone's transitions getting in the way.
+func wallTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// ExpandHashes replaces Jenkins-style H, H/n and H(a-b) fields with values
+// derived from hashKey, so the same job always fires at the same offset.
+func ExpandHashes(expr, hashKey string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	prefix := ""
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		if i := strings.IndexByte(expr, ' '); i >= 0 {
+			prefix, expr = expr[:i+1], strings.TrimSpace(expr[i:])
+		}
+	}
+	if !strings.Contains(expr, "H") || strings.HasPrefix(expr, "@") {
+		return prefix + expr, nil
+	}
+
+	fields := strings.Fields(expr)
+	var bounds []fieldBounds
+	switch len(fields) {
+	case 5:
+		bounds = fiveFieldBounds
+	case 6:
+		bounds = sixFieldBounds
+	default:
+		return "", fmt.Errorf("expected 5 or 6 fields, found %d", len(fields))
+	}
+
+	for i, field := range fields {
+		parts := strings.Split(field, ",")
+		for j, part := range parts {
+			if !strings.HasPrefix(part, "H") {
+				continue
+			}
+			expanded, err := expandHash(part, bounds[i], hashValue(hashKey, i))
+			if err != nil {
+				return "", err
+			}
+			parts[j] = expanded
+		}
+		fields[i] = strings.Join(parts, ",")
+	}
+	return prefix + strings.Join(fields, " "), nil
+}
+
+func expandHash(part string, bounds fieldBounds, hash uint32) (string, error) {
+	rest := part[1:]
+	low, high := bounds.min, bounds.max
+
+	if strings.HasPrefix(rest, "(") {
+		end := strings.IndexByte(rest, ')')
+		if end < 0 {
+			return "", fmt.Errorf("invalid hash range %q", part)
+		}
+		lowText, highText, ok := strings.Cut(rest[1:end], "-")
+		var err1, err2 error
+		low, err1 = strconv.Atoi(lowText)
+		high, err2 = strconv.Atoi(highText)
+		if !ok || err1 != nil || err2 != nil || low > high || low < bounds.min || high > bounds.max {
+			return "", fmt.Errorf("invalid hash range %q", part)
+		}
+		rest = rest[end+1:]
+	}
+
+	if rest == "" {
+		return strconv.Itoa(low + int(hash%uint32(high-low+1))), nil
+	}
+
+	if !strings.HasPrefix(rest, "/") {
+		return "", fmt.Errorf("invalid hash expression %q", part)
+	}
+	step, err := strconv.Atoi(rest[1:])
+	if err != nil || step <= 0 {
+		return "", fmt.Errorf("invalid hash step %q", part)
+	}
+	offset := low + int(hash%uint32(step))
+	if offset > high {
+		offset = low
+	}
+	return fmt.Sprintf("%d-%d/%d", offset, high, step), nil
+}
+
+func hashValue(key string, field int) uint32 {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", key, field)
+	return h.Sum32()
+}
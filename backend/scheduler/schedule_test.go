@@ -0,0 +1,279 @@
+package scheduler
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func formatRuns(runs []time.Time) []string {
+	formatted := make([]string, len(runs))
+	for i, run := range runs {
+		formatted[i] = run.UTC().Format(time.RFC3339)
+	}
+	return formatted
+}
+
+func TestParseScheduleDST(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		timezone string
+		from     string
+		want     []string
+	}{
+		{
+			name:     "fixed time tracks the UTC offset",
+			expr:     "0 9 * * *",
+			timezone: "America/New_York",
+			from:     "2026-03-07T00:00:00Z",
+			want:     []string{"2026-03-07T14:00:00Z", "2026-03-08T13:00:00Z"},
+		},
+		{
+			name:     "time inside the spring-forward gap runs at the shifted instant",
+			expr:     "30 2 * * *",
+			timezone: "America/New_York",
+			from:     "2026-03-07T12:00:00Z",
+			want:     []string{"2026-03-08T07:30:00Z", "2026-03-09T06:30:00Z"},
+		},
+		{
+			name:     "repeated time on fall-back fires once",
+			expr:     "30 1 * * *",
+			timezone: "America/New_York",
+			from:     "2026-10-31T12:00:00Z",
+			want:     []string{"2026-11-01T05:30:00Z", "2026-11-02T06:30:00Z"},
+		},
+		{
+			name:     "hourly job through the spring-forward gap",
+			expr:     "0 * * * *",
+			timezone: "America/New_York",
+			from:     "2026-03-08T05:30:00Z",
+			want:     []string{"2026-03-08T06:00:00Z", "2026-03-08T07:00:00Z", "2026-03-08T08:00:00Z"},
+		},
+		{
+			name:     "hourly job runs in both passes of the fall-back hour",
+			expr:     "0 * * * *",
+			timezone: "America/New_York",
+			from:     "2026-11-01T04:30:00Z",
+			want:     []string{"2026-11-01T05:00:00Z", "2026-11-01T06:00:00Z", "2026-11-01T07:00:00Z"},
+		},
+		{
+			name:     "sub-hourly job keeps its interval through fall-back",
+			expr:     "*/15 * * * *",
+			timezone: "America/New_York",
+			from:     "2026-11-01T05:40:00Z",
+			want:     []string{"2026-11-01T05:45:00Z", "2026-11-01T06:00:00Z", "2026-11-01T06:15:00Z", "2026-11-01T06:30:00Z"},
+		},
+		{
+			name:     "sub-hourly job skips the spring-forward gap without duplicates",
+			expr:     "*/30 * * * *",
+			timezone: "America/New_York",
+			from:     "2026-03-08T06:10:00Z",
+			want:     []string{"2026-03-08T06:30:00Z", "2026-03-08T07:00:00Z", "2026-03-08T07:30:00Z"},
+		},
+		{
+			name:     "stepped hours run on real instants",
+			expr:     "30 */1 * * *",
+			timezone: "America/New_York",
+			from:     "2026-11-01T05:00:00Z",
+			want:     []string{"2026-11-01T05:30:00Z", "2026-11-01T06:30:00Z", "2026-11-01T07:30:00Z"},
+		},
+		{
+			name:     "restart during the repeated hour does not fire again",
+			expr:     "30 1 * * *",
+			timezone: "America/New_York",
+			from:     "2026-11-01T06:10:00Z",
+			want:     []string{"2026-11-02T06:30:00Z"},
+		},
+		{
+			name:     "explicit CRON_TZ overrides the job timezone",
+			expr:     "CRON_TZ=Europe/London 30 1 * * *",
+			timezone: "America/New_York",
+			from:     "2026-03-29T00:00:00Z",
+			want:     []string{"2026-03-29T01:30:00Z", "2026-03-30T00:30:00Z"},
+		},
+		{
+			name:     "seconds field",
+			expr:     "15 30 2 * * *",
+			timezone: "Europe/Berlin",
+			from:     "2026-03-29T00:00:00Z",
+			want:     []string{"2026-03-29T01:30:15Z", "2026-03-30T00:30:15Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.expr, mustLocation(t, tt.timezone), "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			from, _ := time.Parse(time.RFC3339, tt.from)
+			got := formatRuns(NextRuns(schedule, from, len(tt.want)))
+			if len(got) != len(tt.want) {
+				t.Fatalf("runs = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("runs = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseScheduleResultsInJobZone(t *testing.T) {
+	loc := mustLocation(t, "Asia/Tokyo")
+	schedule, err := ParseSchedule("0 9 * * *", loc, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next := schedule.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if next.Location() != loc || next.Hour() != 9 {
+		t.Errorf("next = %s, want 09:00 in %s", next, loc)
+	}
+}
+
+func TestParseScheduleErrors(t *testing.T) {
+	for _, expr := range []string{"", "* * *", "61 * * * *", "CRON_TZ=Mars/Base * * * * *", "CRON_TZ=UTC"} {
+		if _, err := ParseSchedule(expr, time.UTC, ""); err == nil {
+			t.Errorf("ParseSchedule(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestExpandHashes(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		check func(fields []string) bool
+	}{
+		{
+			name:  "plain expression is unchanged",
+			expr:  "*/5 9-17 * * 1-5",
+			check: func(f []string) bool { return strings.Join(f, " ") == "*/5 9-17 * * 1-5" },
+		},
+		{
+			name:  "H minute stays within 0-59",
+			expr:  "H * * * *",
+			check: func(f []string) bool { return inRange(f[0], 0, 59) && f[1] == "*" },
+		},
+		{
+			name:  "H day of month stays within 1-28",
+			expr:  "0 0 H * *",
+			check: func(f []string) bool { return inRange(f[2], 1, 28) },
+		},
+		{
+			name:  "H weekday stays within 0-6",
+			expr:  "0 0 * * H",
+			check: func(f []string) bool { return inRange(f[4], 0, 6) },
+		},
+		{
+			name: "H range stays within its bounds",
+			expr: "H(0-29) H(9-17) * * *",
+			check: func(f []string) bool {
+				return inRange(f[0], 0, 29) && inRange(f[1], 9, 17)
+			},
+		},
+		{
+			name: "H step picks an offset below the step",
+			expr: "H/15 * * * *",
+			check: func(f []string) bool {
+				offset, rest, ok := strings.Cut(f[0], "-")
+				return ok && inRange(offset, 0, 14) && rest == "59/15"
+			},
+		},
+		{
+			name: "H list items expand independently",
+			expr: "0 H,12 * * *",
+			check: func(f []string) bool {
+				hash, fixed, ok := strings.Cut(f[1], ",")
+				return ok && inRange(hash, 0, 23) && fixed == "12"
+			},
+		},
+		{
+			name: "seconds field",
+			expr: "H H * * * *",
+			check: func(f []string) bool {
+				return len(f) == 6 && inRange(f[0], 0, 59) && inRange(f[1], 0, 59)
+			},
+		},
+		{
+			name: "timezone prefix is kept",
+			expr: "CRON_TZ=Europe/Paris H 9 * * *",
+			check: func(f []string) bool {
+				return f[0] == "CRON_TZ=Europe/Paris" && inRange(f[1], 0, 59)
+			},
+		},
+		{
+			name:  "descriptors are left alone",
+			expr:  "@hourly",
+			check: func(f []string) bool { return len(f) == 1 && f[0] == "@hourly" },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expanded, err := ExpandHashes(tt.expr, "job-123")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.check(strings.Fields(expanded)) {
+				t.Errorf("ExpandHashes(%q) = %q", tt.expr, expanded)
+			}
+			if _, err := ParseSchedule(tt.expr, time.UTC, "job-123"); err != nil {
+				t.Errorf("ParseSchedule(%q): %v", tt.expr, err)
+			}
+		})
+	}
+}
+
+func TestExpandHashesIsStablePerKey(t *testing.T) {
+	first, _ := ExpandHashes("H H * * *", "job-a")
+	again, _ := ExpandHashes("H H * * *", "job-a")
+	if first != again {
+		t.Errorf("same key expanded to %q and %q", first, again)
+	}
+
+	distinct := map[string]bool{}
+	for _, key := range []string{"job-a", "job-b", "job-c", "job-d", "job-e", "job-f"} {
+		expanded, _ := ExpandHashes("H H * * *", key)
+		distinct[expanded] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("different keys all expanded to %v", distinct)
+	}
+}
+
+func TestExpandHashesErrors(t *testing.T) {
+	for _, expr := range []string{
+		"H * * *",
+		"H(5) * * * *",
+		"H(30-10) * * * *",
+		"H(0-60) * * * *",
+		"H(a-b) * * * *",
+		"H(0-10 * * * *",
+		"H/0 * * * *",
+		"H/x * * * *",
+		"Hx * * * *",
+	} {
+		if expanded, err := ExpandHashes(expr, "job-123"); err == nil {
+			t.Errorf("ExpandHashes(%q) = %q, want error", expr, expanded)
+		}
+	}
+}
+
+func inRange(field string, low, high int) bool {
+	n, err := strconv.Atoi(field)
+	return err == nil && n >= low && n <= high
+}
@@ -1,18 +1,25 @@
 package scheduler
 
 import (
+	"errors"
 	"log"
+	"sync"
+	"time"
 
 	"github.com/conan-flynn/cronnect/database"
 	"github.com/conan-flynn/cronnect/middleware"
 	"github.com/conan-flynn/cronnect/models"
 	"github.com/conan-flynn/cronnect/queue"
 	"github.com/robfig/cron/v3"
+	"gorm.io/gorm"
 )
 
 var c *cron.Cron
+var reloadMu sync.Mutex
 var queueService *queue.QueueService
 
+var ErrRateLimited = errors.New("rate limit exceeded")
+
 func StartScheduler() {
 	queueService = queue.NewQueueService()
 	c = cron.New()
@@ -25,6 +32,9 @@ func ReloadJobs() {
 }
 
 func loadJobsFromDB() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
 	var jobs []models.Job
 	database.DB.Preload("Executions").Find(&jobs)
 
@@ -32,15 +42,29 @@ func loadJobsFromDB() {
 	c = cron.New()
 
 	for _, job := range jobs {
+		if (!job.Recurring() && job.RunAt == nil) || job.Type == models.JobTypeHeartbeat || job.Status != models.JobStatusActive {
+			continue
+		}
 		j := job
-		_, err := c.AddFunc(j.Schedule, func() {
-			ScheduleJob(&j)
-		})
+		schedule, err := JobSchedule(&j)
 		if err != nil {
 			log.Printf("Failed to schedule job %s: %v", j.Name, err)
-		} else {
-			log.Printf("Scheduled job: %s", j.Name)
+			continue
 		}
+		c.Schedule(schedule, cron.FuncJob(func() {
+			ScheduleJob(&j)
+		}))
+		log.Printf("Scheduled job: %s", j.Name)
+	}
+
+	if _, err := c.AddFunc("@every 30s", checkHeartbeats); err != nil {
+		log.Printf("Failed to schedule heartbeat checks: %v", err)
+	}
+	if _, err := c.AddFunc("@every 1m", resumeExpiredPauses); err != nil {
+		log.Printf("Failed to schedule auto-resume: %v", err)
+	}
+	if _, err := c.AddFunc("@every 1m", completeExpiredJobs); err != nil {
+		log.Printf("Failed to schedule job expiry: %v", err)
 	}
 
 	c.Start()
@@ -49,6 +73,74 @@ func loadJobsFromDB() {
 func ScheduleJob(job *models.Job) {
 	log.Printf("Scheduling job: %s", job.Name)
 
+	if job.CalendarID != "" {
+		now := time.Now()
+		if entry, excluded := calendarExclusion(job, now); excluded {
+			recordCalendarSkip(job, entry, now)
+			return
+		}
+	}
+
+	_, err := RunJob(job, queue.PublishOptions{Trigger: models.TriggerSchedule})
+	switch {
+	case err == ErrRateLimited || err == queue.ErrJobPending:
+		recordSkippedRun(job, "skipped: "+err.Error(), time.Now())
+	case err != nil:
+		log.Printf("Failed to publish job %s to queue: %v", job.Name, err)
+	}
+
+	recordRun(job, err == nil)
+}
+
+// recordRun counts scheduled runs and completes one-off jobs and jobs that
+// have reached max_runs. A one-off job whose run was not published is left
+// active for completeExpiredJobs, so it never completes without running.
+func recordRun(job *models.Job, published bool) {
+	if job.RunAt == nil && job.MaxRuns == 0 {
+		return
+	}
+
+	runCount := 0
+	if published {
+		database.DB.Model(&models.Job{}).Where("id = ?", job.ID).UpdateColumn("run_count", gorm.Expr("run_count + 1"))
+	}
+	database.DB.Model(&models.Job{}).Where("id = ?", job.ID).Select("run_count").Scan(&runCount)
+
+	if (job.RunAt != nil && published) || (job.MaxRuns > 0 && runCount >= job.MaxRuns) {
+		completeJob(job)
+	}
+}
+
+func completeJob(job *models.Job) {
+	result := database.DB.Model(&models.Job{}).
+		Where("id = ? AND status = ?", job.ID, models.JobStatusActive).
+		Update("status", models.JobStatusCompleted)
+	if result.Error != nil {
+		log.Printf("Failed to complete job %s: %v", job.Name, result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Job %s completed its schedule", job.Name)
+		go ReloadJobs()
+	}
+}
+
+func completeExpiredJobs() {
+	var jobs []models.Job
+	now := time.Now()
+	err := database.DB.Where("status = ? AND type <> ?", models.JobStatusActive, models.JobTypeHeartbeat).
+		Where("(ends_at IS NOT NULL AND ends_at < ?) OR (run_at IS NOT NULL AND run_at < ?)", now, now.Add(-time.Minute)).
+		Find(&jobs).Error
+	if err != nil {
+		log.Printf("Failed to load expired jobs: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		completeJob(&job)
+	}
+}
+
+func RunJob(job *models.Job, opts queue.PublishOptions) (string, error) {
 	rateLimiter := middleware.NewRateLimiter()
 	allowed, remaining, resetAt, err := rateLimiter.CheckRateLimit(job.UserID)
 	if err != nil {
@@ -56,7 +148,7 @@ func ScheduleJob(job *models.Job) {
 	} else if !allowed {
 		log.Printf("Rate limit exceeded for user %s. Job %s skipped. Limit resets at %s. Remaining: %d", 
 			job.UserID, job.Name, resetAt.Format("15:04:05"), remaining)
-		return
+		return "", ErrRateLimited
 	}
 
 	// Record the ping
@@ -64,7 +156,40 @@ func ScheduleJob(job *models.Job) {
 		log.Printf("Failed to record ping for user %s: %v", job.UserID, err)
 	}
 
-	if err := queueService.PublishJob(job); err != nil {
-		log.Printf("Failed to publish job %s to queue: %v", job.Name, err)
+	return queueService.Publish(job, opts)
+}
+
+func resumeExpiredPauses() {
+	var jobs []models.Job
+	if err := database.DB.Where("status = ? AND paused_until IS NOT NULL AND paused_until <= ?", models.JobStatusPaused, time.Now()).Find(&jobs).Error; err != nil {
+		log.Printf("Failed to load paused jobs: %v", err)
+		return
+	}
+
+	resumed := 0
+	for _, job := range jobs {
+		if err := ResumeJob(&job); err != nil {
+			log.Printf("Failed to resume job %s: %v", job.Name, err)
+			continue
+		}
+		log.Printf("Automatically resumed job %s", job.Name)
+		resumed++
+	}
+	if resumed > 0 {
+		go ReloadJobs()
+	}
+}
+
+// ResumeJob reactivates a paused job. Heartbeats restart their ping window so
+// the time spent paused does not count as missed pings.
+func ResumeJob(job *models.Job) error {
+	updates := map[string]interface{}{
+		"status":       models.JobStatusActive,
+		"paused_until": nil,
+	}
+	if job.Type == models.JobTypeHeartbeat && job.LastPingAt != nil {
+		updates["last_ping_at"] = time.Now()
+		updates["heartbeat_state"] = models.HeartbeatUp
 	}
+	return database.DB.Model(job).Updates(updates).Error
 }
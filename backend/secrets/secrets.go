@@ -0,0 +1,97 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/models"
+)
+
+var ErrNotConfigured = errors.New("secrets encryption key is not configured")
+
+var aead cipher.AEAD
+
+func InitEncryption() {
+	raw := os.Getenv("SECRETS_ENCRYPTION_KEY")
+	if raw == "" {
+		log.Println("SECRETS_ENCRYPTION_KEY not set, secrets store is disabled")
+		return
+	}
+
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil || len(key) != 32 {
+		log.Fatal("SECRETS_ENCRYPTION_KEY must be 32 bytes encoded as base64")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		log.Fatal("Failed to initialise secrets cipher:", err)
+	}
+	aead, err = cipher.NewGCM(block)
+	if err != nil {
+		log.Fatal("Failed to initialise secrets cipher:", err)
+	}
+}
+
+func Enabled() bool {
+	return aead != nil
+}
+
+func Encrypt(plaintext []byte, userID string) ([]byte, error) {
+	if aead == nil {
+		return nil, ErrNotConfigured
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, []byte(userID)), nil
+}
+
+func Decrypt(ciphertext []byte, userID string) ([]byte, error) {
+	if aead == nil {
+		return nil, ErrNotConfigured
+	}
+
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	return aead.Open(nil, nonce, sealed, []byte(userID))
+}
+
+func Resolve(userID, name string) (string, error) {
+	var secret models.Secret
+	if err := database.DB.Where("user_id = ? AND name = ?", userID, name).First(&secret).Error; err != nil {
+		return "", fmt.Errorf("secret %q not found", name)
+	}
+
+	plaintext, err := Decrypt(secret.Ciphertext, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %q: %w", name, err)
+	}
+	return string(plaintext), nil
+}
+
+func NewResolver(userID string) func(name string) (string, error) {
+	cache := map[string]string{}
+	return func(name string) (string, error) {
+		if value, ok := cache[name]; ok {
+			return value, nil
+		}
+		value, err := Resolve(userID, name)
+		if err != nil {
+			return "", err
+		}
+		cache[name] = value
+		return value, nil
+	}
+}
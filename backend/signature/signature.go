@@ -0,0 +1,120 @@
+// Package signature signs and verifies outbound cronnect requests.
+//
+// Services receiving calls from cronnect can import this package and call
+// VerifyRequest with the signing secret configured on the job.
+package signature
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderName       = "Cronnect-Signature"
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingHeader = errors.New("missing signature header")
+	ErrInvalidHeader = errors.New("malformed signature header")
+	ErrExpired       = errors.New("signature timestamp outside tolerance")
+	ErrMismatch      = errors.New("signature does not match")
+)
+
+func Sign(secret string, timestamp int64, method, url string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d\n%s\n%s\n", timestamp, strings.ToUpper(method), CanonicalURL(url))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CanonicalURL reduces a URL to the part covered by signatures: scheme, host
+// and request URI, with an empty path written as "/". Fragments and userinfo
+// are never sent to the server and are dropped.
+func CanonicalURL(rawURL string) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host + u.RequestURI()
+}
+
+func Header(secret string, timestamp int64, method, url string, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(secret, timestamp, method, url, body))
+}
+
+func Verify(header, secret, method, url string, body []byte, tolerance time.Duration) error {
+	if header == "" {
+		return ErrMissingHeader
+	}
+
+	var timestamp int64
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return ErrInvalidHeader
+		}
+		switch key {
+		case "t":
+			ts, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidHeader
+			}
+			timestamp = ts
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == 0 || len(signatures) == 0 {
+		return ErrInvalidHeader
+	}
+
+	if tolerance > 0 {
+		age := time.Since(time.Unix(timestamp, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrExpired
+		}
+	}
+
+	expected := Sign(secret, timestamp, method, url, body)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return nil
+		}
+	}
+	return ErrMismatch
+}
+
+// VerifyRequest reads and restores r.Body. The URL is rebuilt from the Host
+// header and X-Forwarded-Proto, so callers behind a proxy that rewrites the
+// host should use Verify with the public URL instead.
+func VerifyRequest(r *http.Request, secret string, tolerance time.Duration) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	url := scheme + "://" + r.Host + r.URL.RequestURI()
+
+	return Verify(r.Header.Get(HeaderName), secret, r.Method, url, body, tolerance)
+}
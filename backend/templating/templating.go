@@ -0,0 +1,167 @@
+package templating
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+)
+
+type Vars struct {
+	JobID       string
+	JobName     string
+	ExecutionID string
+	ScheduledAt time.Time
+	RetryCount  int
+	Attempt     int
+	CallbackURL string
+	Vars        map[string]string
+	Upstream    *models.UpstreamContext
+}
+
+var funcs = template.FuncMap{
+	"now":          time.Now,
+	"utc":          func(t time.Time) time.Time { return t.UTC() },
+	"addDays":      func(days int, t time.Time) time.Time { return t.AddDate(0, 0, days) },
+	"addMonths":    func(months int, t time.Time) time.Time { return t.AddDate(0, months, 0) },
+	"addHours":     func(hours int, t time.Time) time.Time { return t.Add(time.Duration(hours) * time.Hour) },
+	"addMinutes":   func(minutes int, t time.Time) time.Time { return t.Add(time.Duration(minutes) * time.Minute) },
+	"startOfDay":   startOfDay,
+	"startOfMonth": startOfMonth,
+	"format":       func(layout string, t time.Time) string { return t.Format(layout) },
+	"isoDate":      func(t time.Time) string { return t.Format("2006-01-02") },
+	"rfc3339":      func(t time.Time) string { return t.Format(time.RFC3339) },
+	"unix":         func(t time.Time) int64 { return t.Unix() },
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func IsTemplate(text string) bool {
+	return strings.Contains(text, "{{")
+}
+
+func Validate(text string) error {
+	if !IsTemplate(text) {
+		return nil
+	}
+	if _, err := parse(text, unresolvedSecret); err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	return nil
+}
+
+func parse(text string, resolveSecret SecretResolver) (*template.Template, error) {
+	return template.New("").
+		Funcs(funcs).
+		Funcs(template.FuncMap{"secret": resolveSecret}).
+		Option("missingkey=error").
+		Parse(text)
+}
+
+func unresolvedSecret(name string) (string, error) {
+	return "", errors.New("secrets are only resolved at execution time")
+}
+
+type SecretResolver func(name string) (string, error)
+
+type Renderer struct {
+	vars          *Vars
+	resolveSecret SecretResolver
+}
+
+func NewRenderer(vars *Vars, resolveSecret SecretResolver) *Renderer {
+	if resolveSecret == nil {
+		resolveSecret = unresolvedSecret
+	}
+	return &Renderer{vars: vars, resolveSecret: resolveSecret}
+}
+
+// MaxOutputSize caps what Render may produce so a template such as
+// {{range 100000000}}...{{end}} cannot exhaust memory.
+const MaxOutputSize = 1 << 20
+
+// ErrOutputTooLarge is returned when rendering exceeds its size limit.
+var ErrOutputTooLarge = errors.New("rendered output exceeds size limit")
+
+func (r *Renderer) Render(text string) (string, error) {
+	return r.RenderLimit(text, MaxOutputSize)
+}
+
+// RenderLimit renders text and fails with ErrOutputTooLarge as soon as the
+// output grows beyond limit bytes.
+func (r *Renderer) RenderLimit(text string, limit int) (string, error) {
+	if !IsTemplate(text) {
+		if len(text) > limit {
+			return "", fmt.Errorf("%w of %d bytes", ErrOutputTooLarge, limit)
+		}
+		return text, nil
+	}
+
+	tmpl, err := parse(text, r.resolveSecret)
+	if err != nil {
+		return "", fmt.Errorf("invalid template: %w", err)
+	}
+
+	out := &limitedBuffer{limit: limit}
+	if err := tmpl.Execute(out, r.vars); err != nil {
+		if errors.Is(err, ErrOutputTooLarge) {
+			return "", fmt.Errorf("%w of %d bytes", ErrOutputTooLarge, limit)
+		}
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return out.String(), nil
+}
+
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, ErrOutputTooLarge
+	}
+	return b.Buffer.Write(p)
+}
+
+func (r *Renderer) SetVar(name, value string) {
+	if r.vars.Vars == nil {
+		r.vars.Vars = make(map[string]string)
+	}
+	r.vars.Vars[name] = value
+}
+
+func (r *Renderer) Variables() map[string]string {
+	return r.vars.Vars
+}
+
+func (r *Renderer) CallbackURL() string {
+	return r.vars.CallbackURL
+}
+
+func (r *Renderer) Secret(name string) (string, error) {
+	return r.resolveSecret(name)
+}
+
+// RenderHeaders renders each header value, capping every value at limit bytes.
+func (r *Renderer) RenderHeaders(headers map[string]string, limit int) (map[string]string, error) {
+	rendered := make(map[string]string, len(headers))
+	for key, value := range headers {
+		out, err := r.RenderLimit(value, limit)
+		if err != nil {
+			return nil, fmt.Errorf("header %s: %w", key, err)
+		}
+		rendered[key] = out
+	}
+	return rendered, nil
+}
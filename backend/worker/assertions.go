@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/jsonpath"
+	"github.com/conan-flynn/cronnect/models"
+)
+
+type responseSnapshot struct {
+	StatusCode int
+	URL        *url.URL
+	Header     http.Header
+	Body       []byte
+	Duration   time.Duration
+}
+
+func validateAssertions(assertions []models.Assertion) error {
+	for i, a := range assertions {
+		switch a.Type {
+		case models.AssertionStatusCode:
+			if len(a.StatusCodes) == 0 {
+				return fmt.Errorf("assertion %d: status_codes is required", i)
+			}
+			for _, code := range a.StatusCodes {
+				if code < 100 || code > 599 {
+					return fmt.Errorf("assertion %d: invalid status code %d", i, code)
+				}
+			}
+		case models.AssertionBodyContains:
+			if a.Value == "" {
+				return fmt.Errorf("assertion %d: value is required", i)
+			}
+		case models.AssertionBodyRegex:
+			if _, err := regexp.Compile(a.Value); err != nil {
+				return fmt.Errorf("assertion %d: invalid regex: %v", i, err)
+			}
+		case models.AssertionJSONPath:
+			if err := jsonpath.Validate(a.Property); err != nil {
+				return fmt.Errorf("assertion %d: %v", i, err)
+			}
+		case models.AssertionHeader:
+			if a.Property == "" {
+				return fmt.Errorf("assertion %d: property is required", i)
+			}
+		case models.AssertionResponseTime:
+			if a.MaxMs <= 0 {
+				return fmt.Errorf("assertion %d: max_ms must be positive", i)
+			}
+		default:
+			return errors.New("unknown assertion type: " + a.Type)
+		}
+	}
+	return nil
+}
+
+func evaluateAssertions(assertions []models.Assertion, snap *responseSnapshot) []models.AssertionResult {
+	results := make([]models.AssertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		results = append(results, evaluateAssertion(a, snap))
+	}
+	return results
+}
+
+func hasStatusCodeAssertion(assertions []models.Assertion) bool {
+	for _, a := range assertions {
+		if a.Type == models.AssertionStatusCode {
+			return true
+		}
+	}
+	return false
+}
+
+func firstFailedAssertion(results []models.AssertionResult) *models.AssertionResult {
+	for i := range results {
+		if !results[i].Passed {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+func evaluateAssertion(a models.Assertion, snap *responseSnapshot) models.AssertionResult {
+	result := models.AssertionResult{
+		Type:     a.Type,
+		Property: a.Property,
+		Expected: a.Value,
+	}
+
+	switch a.Type {
+	case models.AssertionStatusCode:
+		codes := make([]string, len(a.StatusCodes))
+		for i, code := range a.StatusCodes {
+			codes[i] = strconv.Itoa(code)
+			if code == snap.StatusCode {
+				result.Passed = true
+			}
+		}
+		result.Expected = strings.Join(codes, ",")
+		result.Actual = strconv.Itoa(snap.StatusCode)
+		if !result.Passed {
+			result.Message = fmt.Sprintf("status %d not in [%s]", snap.StatusCode, result.Expected)
+		}
+
+	case models.AssertionBodyContains:
+		result.Passed = strings.Contains(string(snap.Body), a.Value)
+		if !result.Passed {
+			result.Message = fmt.Sprintf("body does not contain %q", a.Value)
+		}
+
+	case models.AssertionBodyRegex:
+		re, err := regexp.Compile(a.Value)
+		if err != nil {
+			result.Message = fmt.Sprintf("invalid regex: %v", err)
+			break
+		}
+		match := re.Find(snap.Body)
+		result.Passed = match != nil
+		if result.Passed {
+			result.Actual = string(match)
+		} else {
+			result.Message = fmt.Sprintf("body does not match %q", a.Value)
+		}
+
+	case models.AssertionJSONPath:
+		actual, err := jsonpath.LookupString(snap.Body, a.Property)
+		if err != nil {
+			result.Message = err.Error()
+			break
+		}
+		result.Actual = actual
+		result.Passed = actual == a.Value
+		if !result.Passed {
+			result.Message = fmt.Sprintf("%s is %q, expected %q", a.Property, actual, a.Value)
+		}
+
+	case models.AssertionHeader:
+		values, ok := snap.Header[http.CanonicalHeaderKey(a.Property)]
+		if !ok {
+			result.Message = fmt.Sprintf("header %s is missing", a.Property)
+			break
+		}
+		result.Actual = strings.Join(values, ", ")
+		result.Passed = a.Value == "" || result.Actual == a.Value
+		if !result.Passed {
+			result.Message = fmt.Sprintf("header %s is %q, expected %q", a.Property, result.Actual, a.Value)
+		}
+
+	case models.AssertionResponseTime:
+		elapsed := snap.Duration.Milliseconds()
+		result.Expected = fmt.Sprintf("<= %dms", a.MaxMs)
+		result.Actual = fmt.Sprintf("%dms", elapsed)
+		result.Passed = elapsed <= int64(a.MaxMs)
+		if !result.Passed {
+			result.Message = fmt.Sprintf("response took %dms, limit is %dms", elapsed, a.MaxMs)
+		}
+
+	default:
+		result.Message = "unknown assertion type: " + a.Type
+	}
+
+	return result
+}
@@ -0,0 +1,146 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/jsonpath"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+const CallbackHeader = "Cronnect-Callback-URL"
+
+var (
+	defaultPollSuccessValues = []string{"success", "succeeded", "completed", "done"}
+	defaultPollFailureValues = []string{"failed", "failure", "error", "cancelled"}
+)
+
+func callbackURL(token string) string {
+	base := os.Getenv("PUBLIC_BASE_URL")
+	if base == "" {
+		base = "http://localhost:8080"
+	}
+	return strings.TrimRight(base, "/") + "/callbacks/" + token
+}
+
+func withHeader(headers map[string]string, key, value string) map[string]string {
+	merged := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		merged[k] = v
+	}
+	merged[key] = value
+	return merged
+}
+
+// awaitCompletion keeps the execution open once the target has accepted the
+// work; the queue then waits for a callback or polls the status URL.
+func awaitCompletion(payload *models.JobPayload, snap *responseSnapshot, result *models.JobResult) {
+	switch payload.Completion.Mode {
+	case models.CompletionCallback:
+		result.Status = "running"
+	case models.CompletionPoll:
+		location := snap.Header.Get("Location")
+		if location == "" {
+			result.Status = "failed"
+			result.ErrorMessage = "Poll completion requires a Location header in the response"
+			return
+		}
+		statusURL, err := snap.URL.Parse(location)
+		if err != nil {
+			result.Status = "failed"
+			result.ErrorMessage = fmt.Sprintf("Invalid Location header: %v", err)
+			return
+		}
+		result.Status = "running"
+		result.AsyncPoll = &models.AsyncPollState{
+			StatusURL: statusURL.String(),
+			Deadline:  time.Now().Add(payload.Completion.Deadline()),
+		}
+	}
+}
+
+func validateCompletion(completion *models.JobCompletion) error {
+	if completion == nil {
+		return nil
+	}
+	if completion.Mode != models.CompletionCallback && completion.Mode != models.CompletionPoll {
+		return fmt.Errorf("completion mode must be %q or %q", models.CompletionCallback, models.CompletionPoll)
+	}
+	if completion.DeadlineSeconds < 0 || completion.DeadlineSeconds > models.MaxCompletionDeadlineSeconds {
+		return fmt.Errorf("completion deadline_seconds must be between 1 and %d", models.MaxCompletionDeadlineSeconds)
+	}
+	if completion.PollIntervalSeconds < 0 || time.Duration(completion.PollIntervalSeconds)*time.Second > completion.Deadline() {
+		return errors.New("completion poll_interval_seconds must not exceed the deadline")
+	}
+	if completion.StatusPath != "" {
+		if completion.Mode != models.CompletionPoll {
+			return errors.New("completion status_path is only used in poll mode")
+		}
+		if err := jsonpath.Validate(completion.StatusPath); err != nil {
+			return fmt.Errorf("completion status_path: %w", err)
+		}
+	}
+	return nil
+}
+
+func (e *httpExecutor) poll(ctx context.Context, payload *models.JobPayload, renderer *templating.Renderer, result *models.JobResult) {
+	spec := &requestSpec{Method: http.MethodGet, URL: payload.AsyncPoll.StatusURL}
+	snap := e.perform(ctx, payload, spec, renderer, result)
+
+	// Transport errors and 5xx responses are retried on the next poll until
+	// the deadline expires.
+	if snap == nil || snap.StatusCode >= 500 {
+		result.Status = "running"
+		return
+	}
+	if result.Status == "failed" {
+		return
+	}
+
+	completion := payload.Completion
+	if completion == nil || completion.StatusPath == "" {
+		if snap.StatusCode == http.StatusAccepted {
+			result.Status = "running"
+		}
+		return
+	}
+
+	value, err := jsonpath.LookupString(snap.Body, completion.StatusPath)
+	if err != nil {
+		result.Status = "running"
+		return
+	}
+
+	successValues, failureValues := completion.SuccessValues, completion.FailureValues
+	if len(successValues) == 0 {
+		successValues = defaultPollSuccessValues
+	}
+	if len(failureValues) == 0 {
+		failureValues = defaultPollFailureValues
+	}
+
+	switch {
+	case containsFold(successValues, value):
+		result.Status = "success"
+	case containsFold(failureValues, value):
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Target reported status %q", value)
+	default:
+		result.Status = "running"
+	}
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
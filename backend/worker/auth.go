@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/conan-flynn/cronnect/database"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const tokenExpiryMargin = 30 * time.Second
+
+func (e *httpExecutor) applyAuth(req *http.Request, auth *models.JobAuth, renderer *templating.Renderer, forceRefresh bool) error {
+	if auth == nil || auth.Type == "" {
+		return nil
+	}
+
+	switch auth.Type {
+	case models.AuthBasic:
+		username, err := renderer.Render(auth.Username)
+		if err != nil {
+			return fmt.Errorf("username: %w", err)
+		}
+		password, err := renderer.Render(auth.Password)
+		if err != nil {
+			return fmt.Errorf("password: %w", err)
+		}
+		req.SetBasicAuth(username, password)
+
+	case models.AuthBearer:
+		token, err := renderer.Render(auth.Token)
+		if err != nil {
+			return fmt.Errorf("token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+	case models.AuthOAuth2ClientCredentials:
+		token, err := e.clientCredentialsToken(req.Context(), auth, renderer, forceRefresh)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+
+	default:
+		return fmt.Errorf("unknown auth type: %s", auth.Type)
+	}
+
+	return nil
+}
+
+func (e *httpExecutor) retryWithFreshToken(client *http.Client, req *http.Request, auth *models.JobAuth, renderer *templating.Renderer) (*http.Response, error) {
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		retry.Body = body
+	}
+
+	if err := e.applyAuth(retry, auth, renderer, true); err != nil {
+		return nil, err
+	}
+	return client.Do(retry)
+}
+
+func (e *httpExecutor) clientCredentialsToken(ctx context.Context, auth *models.JobAuth, renderer *templating.Renderer, forceRefresh bool) (string, error) {
+	clientSecret, err := renderer.Render(auth.ClientSecret)
+	if err != nil {
+		return "", fmt.Errorf("client secret: %w", err)
+	}
+
+	cacheKey := tokenCacheKey(auth, clientSecret)
+	if !forceRefresh {
+		cached, err := database.RedisClient.Get(ctx, cacheKey).Result()
+		if err == nil && cached != "" {
+			return cached, nil
+		}
+	}
+
+	config := clientcredentials.Config{
+		ClientID:     auth.ClientID,
+		ClientSecret: clientSecret,
+		TokenURL:     auth.TokenURL,
+		Scopes:       auth.Scopes,
+	}
+	if auth.Audience != "" {
+		config.EndpointParams = url.Values{"audience": {auth.Audience}}
+	}
+
+	tokenCtx := context.WithValue(ctx, oauth2.HTTPClient, &http.Client{
+		Transport: e.transport,
+		Timeout:   time.Duration(models.DefaultTimeoutSeconds) * time.Second,
+	})
+	token, err := config.Token(tokenCtx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch oauth2 token: %w", err)
+	}
+
+	ttl := time.Until(token.Expiry) - tokenExpiryMargin
+	if token.Expiry.IsZero() {
+		ttl = 5 * time.Minute
+	}
+	if ttl > 0 {
+		if err := database.RedisClient.Set(ctx, cacheKey, token.AccessToken, ttl).Err(); err != nil {
+			log.Printf("Failed to cache oauth2 token: %v", err)
+		}
+	}
+
+	return token.AccessToken, nil
+}
+
+func tokenCacheKey(auth *models.JobAuth, clientSecret string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		auth.TokenURL,
+		auth.ClientID,
+		clientSecret,
+		strings.Join(auth.Scopes, " "),
+		auth.Audience,
+	}, "\n")))
+	return "oauth2_token:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+type Executor interface {
+	Validate(job *models.Job) error
+	Execute(ctx context.Context, payload *models.JobPayload, renderer *templating.Renderer, result *models.JobResult)
+}
+
+var executors = map[string]Executor{}
+
+func RegisterExecutor(jobType string, executor Executor) {
+	executors[jobType] = executor
+}
+
+func init() {
+	dialer := newGuardedDialer()
+	httpExec := newHTTPExecutor(dialer)
+	RegisterExecutor(models.JobTypeHTTP, httpExec)
+	RegisterExecutor(models.JobTypeTCP, newTCPExecutor(dialer))
+	RegisterExecutor(models.JobTypeScenario, newScenarioExecutor(httpExec))
+	RegisterExecutor(models.JobTypeHeartbeat, &heartbeatExecutor{})
+}
+
+func executorFor(jobType string) (Executor, error) {
+	if jobType == "" {
+		jobType = models.JobTypeHTTP
+	}
+	executor, ok := executors[jobType]
+	if !ok {
+		return nil, fmt.Errorf("unknown job type: %s", jobType)
+	}
+	return executor, nil
+}
+
+func ValidateJob(job *models.Job) error {
+	executor, err := executorFor(job.Type)
+	if err != nil {
+		return err
+	}
+	if job.Completion != nil && job.Type != "" && job.Type != models.JobTypeHTTP {
+		return fmt.Errorf("completion is not supported for %s jobs", job.Type)
+	}
+	return executor.Validate(job)
+}
@@ -0,0 +1,29 @@
+package worker
+
+import (
+	"context"
+	"errors"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+// heartbeatExecutor only validates heartbeat monitors; they are driven by
+// inbound pings and the scheduler's heartbeat check rather than the queue.
+type heartbeatExecutor struct{}
+
+func (e *heartbeatExecutor) Validate(job *models.Job) error {
+	if job.Schedule == "" {
+		return errors.New("heartbeat jobs require a schedule")
+	}
+	if len(job.Dependencies) > 0 {
+		return errors.New("heartbeat jobs cannot depend on other jobs")
+	}
+	_, err := models.ParseHeartbeatConfig(job.Config)
+	return err
+}
+
+func (e *heartbeatExecutor) Execute(ctx context.Context, payload *models.JobPayload, renderer *templating.Renderer, result *models.JobResult) {
+	result.Status = "failed"
+	result.ErrorMessage = "heartbeat jobs are not executed by workers"
+}
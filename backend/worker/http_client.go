@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+const maxCachedTransports = 100
+
+func (e *httpExecutor) clientFor(payload *models.JobPayload, renderer *templating.Renderer) (*http.Client, error) {
+	timeout := payload.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = models.DefaultTimeoutSeconds
+	}
+
+	followRedirects := payload.FollowRedirects == nil || *payload.FollowRedirects
+	maxRedirects := payload.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = models.DefaultMaxRedirects
+	}
+
+	transport, err := e.transportFor(payload.TLS, renderer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(timeout) * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !followRedirects {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			return nil
+		},
+	}, nil
+}
+
+func (e *httpExecutor) transportFor(tlsSettings *models.JobTLS, renderer *templating.Renderer) (*http.Transport, error) {
+	if tlsSettings == nil || (tlsSettings.ClientCertSecret == "" && tlsSettings.CACertSecret == "" && tlsSettings.ServerName == "") {
+		return e.transport, nil
+	}
+
+	var certPEM, keyPEM, caPEM string
+	var err error
+	if tlsSettings.ClientCertSecret != "" {
+		if certPEM, err = renderer.Secret(tlsSettings.ClientCertSecret); err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		if keyPEM, err = renderer.Secret(tlsSettings.ClientKeySecret); err != nil {
+			return nil, fmt.Errorf("client key: %w", err)
+		}
+	}
+	if tlsSettings.CACertSecret != "" {
+		if caPEM, err = renderer.Secret(tlsSettings.CACertSecret); err != nil {
+			return nil, fmt.Errorf("ca bundle: %w", err)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(certPEM + "\x00" + keyPEM + "\x00" + caPEM + "\x00" + tlsSettings.ServerName))
+	cacheKey := hex.EncodeToString(sum[:])
+
+	e.transportsMu.Lock()
+	defer e.transportsMu.Unlock()
+
+	if transport, ok := e.transports[cacheKey]; ok {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: tlsSettings.ServerName,
+	}
+	if certPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if caPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, errors.New("ca bundle contains no valid certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(e.transports) >= maxCachedTransports {
+		for key, transport := range e.transports {
+			transport.CloseIdleConnections()
+			delete(e.transports, key)
+		}
+	}
+
+	transport := e.transport.Clone()
+	transport.TLSClientConfig = tlsConfig
+	e.transports[cacheKey] = transport
+	return transport, nil
+}
+
+func responseLimit(payload *models.JobPayload) int64 {
+	if payload.MaxResponseBytes <= 0 {
+		return models.DefaultMaxResponseBytes
+	}
+	return payload.MaxResponseBytes
+}
@@ -0,0 +1,287 @@
+package worker
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/signature"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+const maxBodyExcerpt = 4096
+
+type httpExecutor struct {
+	transport    *http.Transport
+	transports   map[string]*http.Transport
+	transportsMu sync.Mutex
+}
+
+type requestSpec struct {
+	Method      string
+	URL         string
+	Headers     map[string]string
+	Body        string
+	ContentType string
+	Assertions  []models.Assertion
+}
+
+func newHTTPExecutor(dialer *guardedDialer) *httpExecutor {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &httpExecutor{
+		transport:  transport,
+		transports: make(map[string]*http.Transport),
+	}
+}
+
+func (e *httpExecutor) Execute(ctx context.Context, payload *models.JobPayload, renderer *templating.Renderer, result *models.JobResult) {
+	if payload.AsyncPoll != nil {
+		e.poll(ctx, payload, renderer, result)
+		return
+	}
+
+	spec := specFromPayload(payload)
+	if callbackURL := renderer.CallbackURL(); callbackURL != "" {
+		spec.Headers = withHeader(spec.Headers, CallbackHeader, callbackURL)
+	}
+
+	snap := e.perform(ctx, payload, spec, renderer, result)
+	if payload.Completion != nil && snap != nil && result.Status != "failed" {
+		awaitCompletion(payload, snap, result)
+	}
+}
+
+// perform runs one request with the job-level client settings (timeouts, TLS,
+// auth, signing) and returns the response so scenarios can extract values.
+func (e *httpExecutor) perform(ctx context.Context, payload *models.JobPayload, spec *requestSpec, renderer *templating.Renderer, result *models.JobResult) *responseSnapshot {
+	target, headers, body, err := renderRequest(spec, renderer)
+	if err != nil {
+		log.Printf("Failed to render request for job %s: %v", payload.Name, err)
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Failed to render request: %v", err)
+		return nil
+	}
+
+	var reqBody io.Reader
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, spec.Method, target, reqBody)
+	if err != nil {
+		err = redactURLError(err, spec.URL)
+		log.Printf("Failed to create request for job %s: %v", payload.Name, err)
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Failed to create request: %v", err)
+		return nil
+	}
+
+	if spec.ContentType != "" {
+		req.Header.Set("Content-Type", spec.ContentType)
+	}
+
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	if payload.SigningSecret != "" {
+		signingKey, err := renderer.Secret(payload.SigningSecret)
+		if err != nil {
+			log.Printf("Failed to resolve signing secret for job %s: %v", payload.Name, err)
+			result.Status = "failed"
+			result.ErrorMessage = fmt.Sprintf("Failed to resolve signing secret: %v", err)
+			return nil
+		}
+		req.Header.Set(signature.HeaderName, signature.Header(signingKey, time.Now().Unix(), req.Method, req.URL.String(), []byte(body)))
+	}
+
+	if err := e.applyAuth(req, payload.Auth, renderer, false); err != nil {
+		log.Printf("Failed to apply auth for job %s: %v", payload.Name, err)
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Failed to apply auth: %v", err)
+		return nil
+	}
+
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if addr, ok := info.Conn.RemoteAddr().(*net.TCPAddr); ok {
+				result.RemoteIP = addr.IP.String()
+			}
+		},
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	client, err := e.clientFor(payload, renderer)
+	if err != nil {
+		log.Printf("Failed to configure client for job %s: %v", payload.Name, err)
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Failed to configure TLS: %v", err)
+		return nil
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized && payload.Auth != nil && payload.Auth.Type == models.AuthOAuth2ClientCredentials {
+		log.Printf("Job %s got 401, refreshing oauth2 token", payload.Name)
+		resp.Body.Close()
+		resp, err = e.retryWithFreshToken(client, req, payload.Auth, renderer)
+	}
+	if err != nil {
+		result.Status = "failed"
+		result.LatencyMs = time.Since(start).Milliseconds()
+		var blocked *BlockedDestinationError
+		if errors.As(err, &blocked) {
+			log.Printf("Job %s targets a blocked destination: %v", payload.Name, blocked)
+			result.ErrorMessage = fmt.Sprintf("Blocked destination: %v", blocked)
+			return nil
+		}
+		err = redactURLError(err, spec.URL)
+		log.Printf("Request failed for job %s: %v", payload.Name, err)
+		result.ErrorMessage = fmt.Sprintf("HTTP request failed: %v", err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	limit := responseLimit(payload)
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if int64(len(respBody)) > limit {
+		respBody = respBody[:limit]
+		log.Printf("Response for job %s exceeded %d bytes, truncated", payload.Name, limit)
+	}
+	result.LatencyMs = time.Since(start).Milliseconds()
+	result.ResponseCode = resp.StatusCode
+	result.ResponseHeaders = flattenHeaders(resp.Header)
+	if err != nil {
+		log.Printf("Failed to read response body for job %s: %v", payload.Name, err)
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Failed to read response: %v", err)
+		return nil
+	}
+
+	result.ResponseBody = truncateBody(respBody)
+
+	snap := &responseSnapshot{
+		StatusCode: resp.StatusCode,
+		URL:        resp.Request.URL,
+		Header:     resp.Header,
+		Body:       respBody,
+		Duration:   time.Duration(result.LatencyMs) * time.Millisecond,
+	}
+	result.AssertionResults = evaluateAssertions(spec.Assertions, snap)
+
+	statusOK := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if hasStatusCodeAssertion(spec.Assertions) {
+		statusOK = true
+	}
+
+	if !statusOK {
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("HTTP status %d: %s", resp.StatusCode, result.ResponseBody)
+		log.Printf("Job %s failed with status %d: %s", payload.Name, resp.StatusCode, result.ResponseBody)
+	} else if failed := firstFailedAssertion(result.AssertionResults); failed != nil {
+		result.Status = "failed"
+		result.ErrorMessage = fmt.Sprintf("Assertion %s failed: %s", failed.Type, failed.Message)
+		log.Printf("Job %s failed assertion %s: %s", payload.Name, failed.Type, failed.Message)
+	} else {
+		result.Status = "success"
+		log.Printf("Job %s completed successfully with status %d", payload.Name, resp.StatusCode)
+		log.Printf("Response body: %s", result.ResponseBody)
+	}
+
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		recordCertificate(payload, result, resp.TLS.PeerCertificates[0])
+	}
+
+	return snap
+}
+
+func recordCertificate(payload *models.JobPayload, result *models.JobResult, leaf *x509.Certificate) {
+	expiresAt := leaf.NotAfter
+	result.CertExpiresAt = &expiresAt
+	result.CertIssuer = leaf.Issuer.String()
+	result.CertSANs = append([]string{}, leaf.DNSNames...)
+	for _, ip := range leaf.IPAddresses {
+		result.CertSANs = append(result.CertSANs, ip.String())
+	}
+
+	warningDays := payload.CertExpiryWarningDays
+	if warningDays > 0 && result.Status == "success" && time.Until(expiresAt) < time.Duration(warningDays)*24*time.Hour {
+		result.Status = "warning"
+		result.ErrorMessage = fmt.Sprintf("TLS certificate expires %s (within %d days)", expiresAt.Format(time.RFC3339), warningDays)
+		log.Printf("Job %s: %s", payload.Name, result.ErrorMessage)
+	}
+}
+
+func specFromPayload(payload *models.JobPayload) *requestSpec {
+	return &requestSpec{
+		Method:      payload.Method,
+		URL:         payload.URL,
+		Headers:     payload.Headers,
+		Body:        payload.Body,
+		ContentType: payload.ContentType,
+		Assertions:  payload.Assertions,
+	}
+}
+
+func renderRequest(spec *requestSpec, renderer *templating.Renderer) (string, map[string]string, string, error) {
+	url, err := renderer.RenderLimit(spec.URL, maxRenderedURLSize)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("url: %w", err)
+	}
+
+	headers, err := renderer.RenderHeaders(spec.Headers, maxHeaderValueSize)
+	if err != nil {
+		return "", nil, "", err
+	}
+
+	body, err := renderer.RenderLimit(spec.Body, maxRequestBodySize)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("body: %w", err)
+	}
+
+	return url, headers, body, nil
+}
+
+// redactURLError replaces the rendered URL in a *url.Error with its template so
+// interpolated secrets never reach logs or stored executions.
+func redactURLError(err error, templateURL string) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+	return fmt.Errorf("%s %q: %w", urlErr.Op, templateURL, urlErr.Err)
+}
+
+func truncateBody(body []byte) string {
+	excerpt := body
+	if len(excerpt) > maxBodyExcerpt {
+		excerpt = excerpt[:maxBodyExcerpt]
+	}
+	text := strings.ToValidUTF8(strings.ReplaceAll(string(excerpt), "\x00", ""), "\uFFFD")
+	if len(body) > maxBodyExcerpt {
+		text += "...(truncated)"
+	}
+	return text
+}
+
+func flattenHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for key, values := range header {
+		flat[key] = strings.Join(values, ", ")
+	}
+	return flat
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+func TestRedactURLError(t *testing.T) {
+	const template = `https://api.example.com/v1?api_key={{secret "API_KEY"}}`
+
+	_, parseErr := http.NewRequest(http.MethodGet, "https://api.example.com/v1?api_key=SUPERSECRET\x7f", nil)
+	_, doErr := (&http.Client{}).Get("unsupported://api.example.com/v1?api_key=SUPERSECRET")
+	plain := errors.New("connection reset")
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"parse error", parseErr, `parse "https://api.example.com/v1?api_key={{secret \"API_KEY\"}}"`},
+		{"transport error", doErr, `Get "https://api.example.com/v1?api_key={{secret \"API_KEY\"}}"`},
+		{"other error", plain, "connection reset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err == nil {
+				t.Fatal("expected an error to redact")
+			}
+			got := redactURLError(tt.err, template).Error()
+			if strings.Contains(got, "SUPERSECRET") {
+				t.Errorf("error leaks the secret: %s", got)
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("error = %s, want prefix %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderRequestLimits(t *testing.T) {
+	huge := `{{range 100000000}}x{{end}}`
+
+	tests := []struct {
+		name string
+		spec requestSpec
+		want string
+	}{
+		{"url", requestSpec{URL: "https://example.com/" + huge}, "url: "},
+		{"header", requestSpec{URL: "https://example.com", Headers: map[string]string{"X-Big": huge}}, "header X-Big: "},
+		{"body", requestSpec{URL: "https://example.com", Body: `{{range 65537}}x{{end}}`}, "body: "},
+	}
+
+	renderer := templating.NewRenderer(&templating.Vars{}, nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := renderRequest(&tt.spec, renderer)
+			if !errors.Is(err, templating.ErrOutputTooLarge) {
+				t.Fatalf("err = %v, want ErrOutputTooLarge", err)
+			}
+			if !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+
+	_, _, body, err := renderRequest(&requestSpec{URL: "https://example.com", Body: `{{range 65536}}x{{end}}`}, renderer)
+	if err != nil || len(body) != maxRequestBodySize {
+		t.Errorf("body at the limit: len = %d, err = %v", len(body), err)
+	}
+}
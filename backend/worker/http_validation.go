@@ -0,0 +1,186 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+const (
+	maxRequestBodySize = 64 * 1024
+	maxRedirectsLimit  = 20
+
+	// Rendered templates are capped as well as the template text itself.
+	maxRenderedURLSize = 8 * 1024
+	maxHeaderValueSize = 8 * 1024
+)
+
+var allowedMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+func (e *httpExecutor) Validate(job *models.Job) error {
+	if err := validateTargetURL(job.URL); err != nil {
+		return err
+	}
+	if err := validateRequestFields(job.Method, job.Headers, job.Body, job.ContentType); err != nil {
+		return err
+	}
+	if err := validateAssertions(job.Assertions); err != nil {
+		return err
+	}
+	if err := validateExecutionLimits(job); err != nil {
+		return err
+	}
+	if err := validateTemplates(job.URL, job.Headers, job.Body); err != nil {
+		return err
+	}
+	if err := validateCompletion(job.Completion); err != nil {
+		return err
+	}
+	return validateAuth(job.Auth)
+}
+
+func validateRequestFields(method string, headers map[string]string, body, contentType string) error {
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !allowedMethods[method] {
+		return errors.New("invalid http method")
+	}
+
+	for key := range headers {
+		if !isValidHeaderName(key) {
+			return errors.New("invalid header name: " + key)
+		}
+		if strings.EqualFold(key, "Host") || strings.EqualFold(key, "Content-Length") {
+			return errors.New("header cannot be overridden: " + key)
+		}
+	}
+
+	if len(body) > maxRequestBodySize {
+		return errors.New("request body too large")
+	}
+	if body != "" && (method == http.MethodGet || method == http.MethodHead) {
+		return errors.New("request body is not allowed for " + method + " requests")
+	}
+
+	if contentType != "" {
+		if _, _, err := mime.ParseMediaType(contentType); err != nil {
+			return errors.New("invalid content type")
+		}
+	}
+
+	return nil
+}
+
+func validateTargetURL(rawURL string) error {
+	if templating.IsTemplate(rawURL) {
+		return nil
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http(s) url")
+	}
+	return nil
+}
+
+func validateTemplates(rawURL string, headers map[string]string, body string) error {
+	if err := templating.Validate(rawURL); err != nil {
+		return fmt.Errorf("url: %w", err)
+	}
+	for key, value := range headers {
+		if err := templating.Validate(value); err != nil {
+			return fmt.Errorf("header %s: %w", key, err)
+		}
+	}
+	if err := templating.Validate(body); err != nil {
+		return fmt.Errorf("body: %w", err)
+	}
+	return nil
+}
+
+func validateAuth(auth *models.JobAuth) error {
+	if auth == nil || auth.Type == "" {
+		return nil
+	}
+
+	switch auth.Type {
+	case models.AuthBasic:
+		if auth.Username == "" {
+			return errors.New("auth.username is required")
+		}
+		if err := validateSecretReference("auth.password", auth.Password); err != nil {
+			return err
+		}
+	case models.AuthBearer:
+		if err := validateSecretReference("auth.token", auth.Token); err != nil {
+			return err
+		}
+	case models.AuthOAuth2ClientCredentials:
+		parsed, err := url.Parse(auth.TokenURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.New("auth.token_url must be an http(s) url")
+		}
+		if auth.ClientID == "" {
+			return errors.New("auth.client_id is required")
+		}
+		if err := validateSecretReference("auth.client_secret", auth.ClientSecret); err != nil {
+			return err
+		}
+	default:
+		return errors.New("unknown auth type: " + auth.Type)
+	}
+
+	return templating.Validate(auth.Username)
+}
+
+func validateSecretReference(field, value string) error {
+	if !templating.IsTemplate(value) {
+		return fmt.Errorf(`%s must reference a stored secret, e.g. {{ secret "name" }}`, field)
+	}
+	if err := templating.Validate(value); err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	return nil
+}
+
+func validateExecutionLimits(job *models.Job) error {
+	if job.TimeoutSeconds < 0 || job.TimeoutSeconds > models.MaxTimeoutSeconds {
+		return fmt.Errorf("timeout_seconds must be between 1 and %d", models.MaxTimeoutSeconds)
+	}
+	if job.MaxRedirects < 0 || job.MaxRedirects > maxRedirectsLimit {
+		return fmt.Errorf("max_redirects must be between 1 and %d", maxRedirectsLimit)
+	}
+	if job.MaxResponseBytes < 0 || job.MaxResponseBytes > models.MaxResponseBytesLimit {
+		return fmt.Errorf("max_response_bytes must be between 1 and %d", models.MaxResponseBytesLimit)
+	}
+	if job.CertExpiryWarningDays < 0 || job.CertExpiryWarningDays > 365 {
+		return errors.New("cert_expiry_warning_days must be between 1 and 365")
+	}
+	return nil
+}
+
+func isValidHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 127 || r <= ' ' || strings.ContainsRune("\"(),/:;<=>?@[\\]{}", r) {
+			return false
+		}
+	}
+	return true
+}
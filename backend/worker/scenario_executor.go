@@ -0,0 +1,210 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/conan-flynn/cronnect/jsonpath"
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+const maxScenarioSteps = 20
+
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type scenarioExecutor struct {
+	http *httpExecutor
+}
+
+func newScenarioExecutor(http *httpExecutor) *scenarioExecutor {
+	return &scenarioExecutor{http: http}
+}
+
+func parseScenarioConfig(raw json.RawMessage) (*models.ScenarioConfig, error) {
+	var config models.ScenarioConfig
+	if len(raw) == 0 {
+		return nil, errors.New("config.steps is required")
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("invalid scenario config: %w", err)
+	}
+	if len(config.Steps) == 0 || len(config.Steps) > maxScenarioSteps {
+		return nil, fmt.Errorf("scenario must have between 1 and %d steps", maxScenarioSteps)
+	}
+	return &config, nil
+}
+
+func (e *scenarioExecutor) Validate(job *models.Job) error {
+	config, err := parseScenarioConfig(job.Config)
+	if err != nil {
+		return err
+	}
+
+	for i, step := range config.Steps {
+		if err := validateScenarioStep(&step); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, step.Name, err)
+		}
+	}
+
+	if err := validateExecutionLimits(job); err != nil {
+		return err
+	}
+	return validateAuth(job.Auth)
+}
+
+func validateScenarioStep(step *models.ScenarioStep) error {
+	if step.Name == "" {
+		return errors.New("name is required")
+	}
+	if err := validateTargetURL(step.URL); err != nil {
+		return err
+	}
+	if err := validateRequestFields(strings.ToUpper(step.Method), step.Headers, step.Body, step.ContentType); err != nil {
+		return err
+	}
+	if err := validateAssertions(step.Assertions); err != nil {
+		return err
+	}
+	if err := validateTemplates(step.URL, step.Headers, step.Body); err != nil {
+		return err
+	}
+
+	for _, extraction := range step.Extract {
+		if !variableNamePattern.MatchString(extraction.Var) {
+			return fmt.Errorf("invalid variable name %q", extraction.Var)
+		}
+		switch extraction.Source {
+		case models.ExtractJSONPath:
+			if err := jsonpath.Validate(extraction.Expression); err != nil {
+				return err
+			}
+		case models.ExtractRegex:
+			re, err := regexp.Compile(extraction.Expression)
+			if err != nil {
+				return fmt.Errorf("invalid regex: %v", err)
+			}
+			if re.NumSubexp() > 1 {
+				return errors.New("extraction regex must have at most one capture group")
+			}
+		case models.ExtractHeader:
+			if extraction.Expression == "" {
+				return errors.New("header extraction requires a header name")
+			}
+		default:
+			return errors.New("unknown extraction source: " + extraction.Source)
+		}
+	}
+
+	return nil
+}
+
+func (e *scenarioExecutor) Execute(ctx context.Context, payload *models.JobPayload, renderer *templating.Renderer, result *models.JobResult) {
+	config, err := parseScenarioConfig(payload.Config)
+	if err != nil {
+		result.Status = "failed"
+		result.ErrorMessage = err.Error()
+		return
+	}
+
+	result.Status = "success"
+	failed := false
+
+	for _, step := range config.Steps {
+		if failed && !step.AlwaysRun {
+			result.StepResults = append(result.StepResults, models.StepResult{Name: step.Name, Status: "skipped"})
+			continue
+		}
+
+		stepResult := &models.JobResult{ExecutionID: payload.ExecutionID}
+		spec := &requestSpec{
+			Method:      strings.ToUpper(step.Method),
+			URL:         step.URL,
+			Headers:     step.Headers,
+			Body:        step.Body,
+			ContentType: step.ContentType,
+			Assertions:  step.Assertions,
+		}
+		snap := e.http.perform(ctx, payload, spec, renderer, stepResult)
+
+		record := models.StepResult{
+			Name:             step.Name,
+			Status:           stepResult.Status,
+			ResponseCode:     stepResult.ResponseCode,
+			LatencyMs:        stepResult.LatencyMs,
+			ErrorMessage:     stepResult.ErrorMessage,
+			ResponseBody:     stepResult.ResponseBody,
+			AssertionResults: stepResult.AssertionResults,
+		}
+
+		if snap != nil && stepResult.Status != "failed" {
+			for _, extraction := range step.Extract {
+				value, err := extractValue(extraction, snap)
+				if err != nil {
+					record.Status = "failed"
+					record.ErrorMessage = fmt.Sprintf("Failed to extract %s: %v", extraction.Var, err)
+					break
+				}
+				renderer.SetVar(extraction.Var, value)
+				record.Extracted = append(record.Extracted, extraction.Var)
+			}
+		}
+
+		result.StepResults = append(result.StepResults, record)
+		result.LatencyMs += stepResult.LatencyMs
+		result.ResponseCode = stepResult.ResponseCode
+		result.ResponseBody = stepResult.ResponseBody
+		result.ResponseHeaders = stepResult.ResponseHeaders
+		if result.RemoteIP == "" {
+			result.RemoteIP = stepResult.RemoteIP
+		}
+
+		if record.Status == "failed" {
+			if !failed {
+				result.Status = "failed"
+				result.ErrorMessage = fmt.Sprintf("Step %s failed: %s", step.Name, record.ErrorMessage)
+				log.Printf("Job %s: %s", payload.Name, result.ErrorMessage)
+			}
+			failed = true
+		} else if record.Status == "warning" && result.Status == "success" {
+			result.Status = "warning"
+			result.ErrorMessage = record.ErrorMessage
+		}
+	}
+
+	result.Variables = renderer.Variables()
+}
+
+func extractValue(extraction models.Extraction, snap *responseSnapshot) (string, error) {
+	switch extraction.Source {
+	case models.ExtractJSONPath:
+		return jsonpath.LookupString(snap.Body, extraction.Expression)
+	case models.ExtractRegex:
+		re, err := regexp.Compile(extraction.Expression)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindSubmatch(snap.Body)
+		if match == nil {
+			return "", fmt.Errorf("no match for %q", extraction.Expression)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	case models.ExtractHeader:
+		values, ok := snap.Header[http.CanonicalHeaderKey(extraction.Expression)]
+		if !ok || len(values) == 0 {
+			return "", fmt.Errorf("header %s is missing", extraction.Expression)
+		}
+		return values[0], nil
+	default:
+		return "", errors.New("unknown extraction source: " + extraction.Source)
+	}
+}
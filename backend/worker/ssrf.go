@@ -0,0 +1,143 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"100.64.0.0/10",
+	"192.0.0.0/24",
+	"198.18.0.0/15",
+	"240.0.0.0/4",
+	"64:ff9b::/96",
+)
+
+type BlockedDestinationError struct {
+	Host string
+	IP   net.IP
+}
+
+func (e *BlockedDestinationError) Error() string {
+	if e.Host == e.IP.String() {
+		return fmt.Sprintf("blocked destination %s", e.IP)
+	}
+	return fmt.Sprintf("blocked destination %s (resolves to %s)", e.Host, e.IP)
+}
+
+type guardedDialer struct {
+	dialer      *net.Dialer
+	resolver    *net.Resolver
+	allowed     []*net.IPNet
+	allowedOnce sync.Once
+}
+
+func newGuardedDialer() *guardedDialer {
+	return &guardedDialer{
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+		resolver: net.DefaultResolver,
+	}
+}
+
+func loadAllowedNetworks() []*net.IPNet {
+	var allowed []*net.IPNet
+	for _, entry := range strings.Split(os.Getenv("SSRF_ALLOWED_CIDRS"), ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Printf("Ignoring invalid SSRF_ALLOWED_CIDRS entry %q: %v", entry, err)
+			continue
+		}
+		allowed = append(allowed, network)
+	}
+	return allowed
+}
+
+func (g *guardedDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := g.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ipAddr := range addrs {
+		if !g.permitted(ipAddr.IP) {
+			return nil, &BlockedDestinationError{Host: host, IP: ipAddr.IP}
+		}
+	}
+
+	var lastErr error
+	for _, ipAddr := range addrs {
+		conn, err := g.dialer.DialContext(ctx, network, net.JoinHostPort(ipAddr.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no addresses found for %s", host)
+	}
+	return nil, lastErr
+}
+
+func (g *guardedDialer) permitted(ip net.IP) bool {
+	g.allowedOnce.Do(func() {
+		g.allowed = loadAllowedNetworks()
+	})
+	for _, network := range g.allowed {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return !isBlockedIP(ip)
+}
+
+func isBlockedIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsBlockedIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"127.8.9.10", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"172.31.255.255", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fc00::1", true},
+		{"fd12:3456::1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"100.64.0.1", true},
+		{"198.18.0.1", true},
+		{"224.0.0.1", true},
+		{"ff02::1", true},
+		{"255.255.255.255", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"8.8.8.8", false},
+		{"1.1.1.1", false},
+		{"172.32.0.1", false},
+		{"100.128.0.1", false},
+		{"2606:4700:4700::1111", false},
+		{"::ffff:8.8.8.8", false},
+	}
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if ip == nil {
+			t.Fatalf("invalid test IP %q", tt.ip)
+		}
+		if got := isBlockedIP(ip); got != tt.blocked {
+			t.Errorf("isBlockedIP(%s) = %v, want %v", tt.ip, got, tt.blocked)
+		}
+	}
+}
+
+func TestLoadAllowedNetworks(t *testing.T) {
+	t.Setenv("SSRF_ALLOWED_CIDRS", " 10.0.0.0/8, 192.168.1.5 ,fd00::1, not-a-network,")
+	allowed := loadAllowedNetworks()
+	if len(allowed) != 3 {
+		t.Fatalf("parsed %d networks, want 3: %v", len(allowed), allowed)
+	}
+
+	dialer := newGuardedDialer()
+	tests := []struct {
+		ip        string
+		permitted bool
+	}{
+		{"10.20.30.40", true},
+		{"192.168.1.5", true},
+		{"192.168.1.6", false},
+		{"fd00::1", true},
+		{"fd00::2", false},
+		{"127.0.0.1", false},
+		{"93.184.216.34", true},
+	}
+	for _, tt := range tests {
+		if got := dialer.permitted(net.ParseIP(tt.ip)); got != tt.permitted {
+			t.Errorf("permitted(%s) = %v, want %v", tt.ip, got, tt.permitted)
+		}
+	}
+}
+
+func TestGuardedDialerBlocksLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	addr := server.Listener.Addr().String()
+
+	t.Setenv("SSRF_ALLOWED_CIDRS", "")
+	_, err := newGuardedDialer().DialContext(context.Background(), "tcp", addr)
+	var blocked *BlockedDestinationError
+	if !errors.As(err, &blocked) {
+		t.Fatalf("DialContext(%s) error = %v, want BlockedDestinationError", addr, err)
+	}
+
+	t.Setenv("SSRF_ALLOWED_CIDRS", "127.0.0.1")
+	conn, err := newGuardedDialer().DialContext(context.Background(), "tcp", addr)
+	if err != nil {
+		t.Fatalf("DialContext(%s) with allow list: %v", addr, err)
+	}
+	conn.Close()
+}
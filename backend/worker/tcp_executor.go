@@ -0,0 +1,84 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"github.com/conan-flynn/cronnect/models"
+	"github.com/conan-flynn/cronnect/templating"
+)
+
+type tcpConfig struct {
+	Address string `json:"address"`
+}
+
+type tcpExecutor struct {
+	dialer *guardedDialer
+}
+
+func newTCPExecutor(dialer *guardedDialer) *tcpExecutor {
+	return &tcpExecutor{dialer: dialer}
+}
+
+func parseTCPConfig(raw json.RawMessage) (*tcpConfig, error) {
+	var config tcpConfig
+	if len(raw) == 0 {
+		return nil, errors.New("config.address is required")
+	}
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return nil, fmt.Errorf("invalid tcp config: %w", err)
+	}
+	if _, _, err := net.SplitHostPort(config.Address); err != nil {
+		return nil, errors.New("config.address must be host:port")
+	}
+	return &config, nil
+}
+
+func (e *tcpExecutor) Validate(job *models.Job) error {
+	if _, err := parseTCPConfig(job.Config); err != nil {
+		return err
+	}
+	return validateExecutionLimits(job)
+}
+
+func (e *tcpExecutor) Execute(ctx context.Context, payload *models.JobPayload, renderer *templating.Renderer, result *models.JobResult) {
+	config, err := parseTCPConfig(payload.Config)
+	if err != nil {
+		result.Status = "failed"
+		result.ErrorMessage = err.Error()
+		return
+	}
+
+	timeout := payload.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = models.DefaultTimeoutSeconds
+	}
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := e.dialer.DialContext(ctx, "tcp", config.Address)
+	result.LatencyMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = "failed"
+		var blocked *BlockedDestinationError
+		if errors.As(err, &blocked) {
+			result.ErrorMessage = fmt.Sprintf("Blocked destination: %v", blocked)
+		} else {
+			result.ErrorMessage = fmt.Sprintf("TCP connect failed: %v", err)
+		}
+		log.Printf("Job %s: %s", payload.Name, result.ErrorMessage)
+		return
+	}
+	defer conn.Close()
+
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		result.RemoteIP = addr.IP.String()
+	}
+	result.Status = "success"
+}
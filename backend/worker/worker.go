@@ -1,22 +1,24 @@
 package worker
 
 import (
+	"context"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"time"
 
 	"github.com/conan-flynn/cronnect/database"
 	"github.com/conan-flynn/cronnect/models"
 	"github.com/conan-flynn/cronnect/queue"
+	"github.com/conan-flynn/cronnect/secrets"
+	"github.com/conan-flynn/cronnect/templating"
 	"github.com/google/uuid"
 )
 
+const dryRunTimeoutSeconds = 10
+
 type Worker struct {
 	ID           string
 	queueService *queue.QueueService
-	httpClient   *http.Client
 }
 
 func NewWorker() *Worker {
@@ -25,9 +27,6 @@ func NewWorker() *Worker {
 	return &Worker{
 		ID:           workerID,
 		queueService: queue.NewQueueService(),
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
 	}
 }
 
@@ -44,64 +43,96 @@ func (w *Worker) processJob(payload *models.JobPayload) *models.JobResult {
 
 	var execution models.JobExecution
 	database.DB.First(&execution, "id = ?", payload.ExecutionID)
-	execution.Status = "running"
-	database.DB.Save(&execution)
+	if payload.AsyncPoll == nil {
+		execution.Status = "running"
+		database.DB.Save(&execution)
+	} else if execution.Status != "running" {
+		log.Printf("Worker %s: Execution %s already finished, dropping status poll", w.ID, payload.ExecutionID)
+		return &models.JobResult{ExecutionID: payload.ExecutionID, Status: execution.Status}
+	}
 
 	result := &models.JobResult{
 		ExecutionID: payload.ExecutionID,
 		CompletedAt: time.Now(),
+		Attempt:     payload.RetryCount + 1,
 	}
 
 
-	req, err := http.NewRequest(payload.Method, payload.URL, nil)
-	if err != nil {
-		log.Printf("Worker %s: Failed to create request for job %s: %v", w.ID, payload.Name, err)
-		result.Status = "failed"
-		result.ErrorMessage = fmt.Sprintf("Failed to create request: %v", err)
+	vars := newVars(payload)
+
+	if payload.Completion != nil && payload.Completion.Mode == models.CompletionCallback && payload.AsyncPoll == nil {
+		token, err := w.queueService.IssueCallbackToken(payload.ExecutionID, payload.Completion.Deadline())
+		if err != nil {
+			log.Printf("Worker %s: Failed to issue callback token for job %s: %v", w.ID, payload.Name, err)
+			result.Status = "failed"
+			result.ErrorMessage = err.Error()
+			return result
+		}
+		vars.CallbackURL = callbackURL(token)
+	}
+
+	if err := execute(context.Background(), payload, vars, result); err != nil {
+		log.Printf("Worker %s: Cannot execute job %s: %v", w.ID, payload.Name, err)
 		return result
 	}
+	log.Printf("Worker %s: Job %s finished with status %s", w.ID, payload.Name, result.Status)
 
+	return result
+}
 
-	for key, value := range payload.Headers {
-		req.Header.Set(key, value)
+// DryRun executes an unsaved job definition synchronously without touching
+// the queue or recording an execution. The whole run, including every
+// scenario step and token fetch, shares one dryRunTimeoutSeconds deadline.
+func DryRun(job *models.Job) *models.JobResult {
+	payload := queue.BuildPayload(job, "")
+	payload.Completion = nil
+	payload.MaxRetries = 0
+	if payload.TimeoutSeconds <= 0 || payload.TimeoutSeconds > dryRunTimeoutSeconds {
+		payload.TimeoutSeconds = dryRunTimeoutSeconds
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), dryRunTimeoutSeconds*time.Second)
+	defer cancel()
 
-	resp, err := w.httpClient.Do(req)
-	if err != nil {
-		log.Printf("Worker %s: Request failed for job %s: %v", w.ID, payload.Name, err)
-		result.Status = "failed"
-		result.ErrorMessage = fmt.Sprintf("HTTP request failed: %v", err)
-		return result
+	result := &models.JobResult{Attempt: 1}
+	if err := execute(ctx, &payload, newVars(&payload), result); err != nil {
+		log.Printf("Cannot dry-run job %s: %v", payload.Name, err)
 	}
-	defer resp.Body.Close()
+	result.CompletedAt = time.Now()
 
+	return result
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Worker %s: Failed to read response body for job %s: %v", w.ID, payload.Name, err)
-		result.Status = "failed"
-		result.ErrorMessage = fmt.Sprintf("Failed to read response: %v", err)
-		return result
+func newVars(payload *models.JobPayload) *templating.Vars {
+	scheduledAt := payload.ScheduledAt
+	if loc, err := time.LoadLocation(payload.Timezone); err == nil {
+		scheduledAt = scheduledAt.In(loc)
 	}
 
-	result.ResponseCode = resp.StatusCode
-
+	return &templating.Vars{
+		JobID:       payload.JobID,
+		JobName:     payload.Name,
+		ExecutionID: payload.ExecutionID,
+		ScheduledAt: scheduledAt,
+		RetryCount:  payload.RetryCount,
+		Attempt:     payload.RetryCount + 1,
+		Upstream:    payload.Upstream,
+	}
+}
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		result.Status = "success"
-		log.Printf("Worker %s: Job %s completed successfully with status %d", w.ID, payload.Name, resp.StatusCode)
-		log.Printf("Worker %s: Response body: %s", w.ID, string(body))
-	} else {
+func execute(ctx context.Context, payload *models.JobPayload, vars *templating.Vars, result *models.JobResult) error {
+	executor, err := executorFor(payload.Type)
+	if err != nil {
 		result.Status = "failed"
-		result.ErrorMessage = fmt.Sprintf("HTTP status %d: %s", resp.StatusCode, string(body))
-		log.Printf("Worker %s: Job %s failed with status %d: %s", w.ID, payload.Name, resp.StatusCode, string(body))
+		result.ErrorMessage = err.Error()
+		return err
 	}
 
-	return result
+	renderer := templating.NewRenderer(vars, secrets.NewResolver(payload.UserID))
+	executor.Execute(ctx, payload, renderer, result)
+	return nil
 }
 
-
 func StartMultipleWorkers(count int) {
 	for i := 0; i < count; i++ {
 		worker := NewWorker()